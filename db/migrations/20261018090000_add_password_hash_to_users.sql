@@ -0,0 +1,5 @@
+-- migrate:up
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(255);
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN password_hash;
@@ -0,0 +1,11 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN email VARCHAR(255),
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'suspended', 'deleted'));
+
+CREATE UNIQUE INDEX users_email_key ON users (LOWER(email));
+
+-- migrate:down
+DROP INDEX users_email_key;
+ALTER TABLE users DROP COLUMN status, DROP COLUMN email;
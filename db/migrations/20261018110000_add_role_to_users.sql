@@ -0,0 +1,6 @@
+-- migrate:up
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('admin', 'member'));
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,10 @@
+-- migrate:up
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('admin', 'moderator', 'member'));
+
+-- migrate:down
+UPDATE users SET role = 'member' WHERE role = 'moderator';
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('admin', 'member'));
@@ -0,0 +1,17 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE posts
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_posts_deleted_at ON posts (deleted_at);
+
+-- migrate:down
+DROP INDEX idx_posts_deleted_at;
+ALTER TABLE posts DROP COLUMN deleted_at, DROP COLUMN updated_at;
+
+DROP INDEX idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at, DROP COLUMN updated_at;
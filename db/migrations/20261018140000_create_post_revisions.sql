@@ -0,0 +1,15 @@
+-- migrate:up
+CREATE TABLE post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    editor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    rolled_back_from INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (post_id, revision)
+);
+
+-- migrate:down
+drop table post_revisions;
@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE posts ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- migrate:down
+ALTER TABLE posts DROP COLUMN version;
+ALTER TABLE users DROP COLUMN version;
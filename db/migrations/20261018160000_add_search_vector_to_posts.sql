@@ -0,0 +1,12 @@
+-- migrate:up
+-- The column is rebuilt from a configurable language in
+-- 20261018220000_add_search_language_to_posts.sql.
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+) STORED;
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+
+-- migrate:down
+DROP INDEX idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
@@ -0,0 +1,7 @@
+-- migrate:up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+
+-- migrate:down
+DROP INDEX idx_users_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,14 @@
+-- migrate:up
+CREATE TABLE follows (
+    follower_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+CREATE INDEX idx_follows_followee_id ON follows (followee_id);
+CREATE INDEX idx_posts_user_id_id ON posts (user_id, id DESC);
+
+-- migrate:down
+drop index idx_posts_user_id_id;
+drop table follows;
@@ -0,0 +1,23 @@
+-- migrate:up
+-- search_language is the text search configuration search_vector is built
+-- with. The application sets its default to SEARCH_LANGUAGE at startup and
+-- moves posts stored with another configuration to it, so the vectors always
+-- match the configuration searches are stemmed with.
+ALTER TABLE posts ADD COLUMN search_language regconfig NOT NULL DEFAULT 'english';
+DROP INDEX idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(search_language, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(search_language, coalesce(content, '')), 'B')
+) STORED;
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+
+-- migrate:down
+DROP INDEX idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+) STORED;
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+ALTER TABLE posts DROP COLUMN search_language;
@@ -0,0 +1,35 @@
+package infra
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvDuration reads a Go duration such as "15m" from key, falling back when
+// it is unset or invalid.
+func EnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// EnvInt reads a positive integer from key, falling back when it is unset or
+// invalid.
+func EnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// EnvString reads key, falling back when it is unset or empty.
+func EnvString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,144 @@
+package infra
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/joho/godotenv"
+)
+
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+type TokenClaims struct {
+	UserID uint64    `json:"uid"`
+	Role   string    `json:"role,omitempty"`
+	Type   TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// JWTManager signs and verifies tokens locally, so any instance holding the
+// key (or only the public key for Ed25519) can verify without a network call.
+type JWTManager struct {
+	method     jwt.SigningMethod
+	signKey    interface{}
+	verifyKey  interface{}
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewJWTManager() *JWTManager {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	manager := &JWTManager{
+		issuer:     os.Getenv("JWT_ISSUER"),
+		accessTTL:  EnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		refreshTTL: EnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
+	}
+
+	switch os.Getenv("JWT_ALGORITHM") {
+	case "", "HS256":
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			log.Fatal("JWT_SECRET is required for HS256")
+		}
+		manager = NewHMACJWTManager([]byte(secret), manager.issuer, manager.accessTTL, manager.refreshTTL)
+	case "EdDSA":
+		manager.method = jwt.SigningMethodEdDSA
+		if pem := os.Getenv("JWT_PRIVATE_KEY"); pem != "" {
+			key, err := jwt.ParseEdPrivateKeyFromPEM([]byte(pem))
+			if err != nil {
+				log.Fatal("Failed to parse JWT_PRIVATE_KEY: ", err)
+			}
+			manager.signKey = key
+			manager.verifyKey = key.(ed25519.PrivateKey).Public()
+		}
+		if pem := os.Getenv("JWT_PUBLIC_KEY"); pem != "" {
+			key, err := jwt.ParseEdPublicKeyFromPEM([]byte(pem))
+			if err != nil {
+				log.Fatal("Failed to parse JWT_PUBLIC_KEY: ", err)
+			}
+			manager.verifyKey = key
+		}
+		if manager.verifyKey == nil {
+			log.Fatal("JWT_PRIVATE_KEY or JWT_PUBLIC_KEY is required for EdDSA")
+		}
+	default:
+		log.Fatal("Unsupported JWT_ALGORITHM: ", os.Getenv("JWT_ALGORITHM"))
+	}
+
+	return manager
+}
+
+// NewHMACJWTManager returns an HS256 manager for secret without reading the
+// environment.
+func NewHMACJWTManager(secret []byte, issuer string, accessTTL time.Duration, refreshTTL time.Duration) *JWTManager {
+	return &JWTManager{
+		method:     jwt.SigningMethodHS256,
+		signKey:    secret,
+		verifyKey:  secret,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+func (m *JWTManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+func (m *JWTManager) Generate(userID uint64, role string, tokenType TokenType) (string, error) {
+	if m.signKey == nil {
+		return "", errors.New("token signing key is not configured")
+	}
+
+	ttl := m.accessTTL
+	if tokenType == RefreshToken {
+		ttl = m.refreshTTL
+	}
+
+	now := time.Now()
+	claims := TokenClaims{
+		UserID: userID,
+		Role:   role,
+		Type:   tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(m.method, claims).SignedString(m.signKey)
+}
+
+func (m *JWTManager) Parse(tokenString string, tokenType TokenType) (*TokenClaims, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{m.method.Alg()})}
+	if m.issuer != "" {
+		options = append(options, jwt.WithIssuer(m.issuer))
+	}
+
+	claims := &TokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	}, options...)
+	if err != nil || claims.Type != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -36,6 +36,12 @@ func NewRedisClient() *RedisClient {
 	return &RedisClient{client: rdb}
 }
 
+// NewRedisClientAt connects to the server at addr without reading the
+// environment.
+func NewRedisClientAt(addr string) *RedisClient {
+	return &RedisClient{client: redis.NewClient(&redis.Options{Addr: addr})}
+}
+
 func (r *RedisClient) Get(key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
@@ -48,6 +54,66 @@ func (r *RedisClient) Set(key string, value string) error {
 	return r.client.Set(ctx, key, value, 5*time.Minute).Err()
 }
 
+// SetTTL stores value for ttl instead of the default cache lifetime.
+func (r *RedisClient) SetTTL(key string, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores value for ttl only when key does not exist yet, and reports
+// whether it did.
+func (r *RedisClient) SetNX(key string, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 func (r *RedisClient) Delete(key string) error {
 	return r.client.Del(ctx, key).Err()
 }
+
+// Generation returns the current generation of a group of cache keys, such
+// as "posts". Keys that embed it are all abandoned by one Invalidate, without
+// scanning for them, and expire on their own.
+func (r *RedisClient) Generation(group string) int64 {
+	generation, err := r.client.Get(ctx, group+":gen").Int64()
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+// Invalidate moves group to a new generation.
+func (r *RedisClient) Invalidate(group string) error {
+	return r.client.Incr(ctx, group+":gen").Err()
+}
+
+// ScanPrefix lists every "key:*" entry without blocking Redis like KEYS.
+func (r *RedisClient) ScanPrefix(key string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, key+":*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// Incr adds delta to the integer at key, starting from 0 when it is missing,
+// and returns the new value. Counters never expire.
+func (r *RedisClient) Incr(key string, delta int64) (int64, error) {
+	return r.client.IncrBy(ctx, key, delta).Result()
+}
+
+// HIncr adds delta to field of the hash at key, creating both when missing,
+// and returns the new value.
+func (r *RedisClient) HIncr(key string, field string, delta int64) (int64, error) {
+	return r.client.HIncrBy(ctx, key, field, delta).Result()
+}
+
+// HGetAll returns every field of the hash at key, empty when it is missing.
+func (r *RedisClient) HGetAll(key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+// HSet writes the given fields of the hash at key, leaving other fields as
+// they are.
+func (r *RedisClient) HSet(key string, values map[string]interface{}) error {
+	return r.client.HSet(ctx, key, values).Err()
+}
@@ -0,0 +1,21 @@
+package infra
+
+import (
+	"time"
+
+	"User-Post-Backend/infra/logger"
+)
+
+// Every runs job in the background once per interval for the lifetime of the
+// process. A failed run is logged and retried at the next tick.
+func Every(interval time.Duration, name string, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := job(); err != nil {
+				logger.Errorf("%s: %v", name, err)
+			}
+		}
+	}()
+}
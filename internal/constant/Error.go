@@ -1,4 +1,122 @@
 package constant
 
-const ErrRecordNotFound = "record not found"
-const ErrForeignKey = "violates foreign key constraint"
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Every domain error wraps exactly one of them, and the error
+// middleware picks the HTTP status from the kind.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("you are not allowed to perform this action")
+	ErrNotFound     = errors.New("data not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnsupported  = errors.New("unsupported media type")
+	ErrPrecondition = errors.New("precondition failed")
+)
+
+// DomainError is a typed error carrying its kind, a stable machine readable
+// code and, optionally, a client facing detail and the underlying cause. The
+// cause is for logs only and never shown to the client.
+type DomainError struct {
+	Kind    error
+	Code    string
+	Message string
+	Detail  string
+	Err     error
+}
+
+// registered holds every error made by NewError.
+var registered []*DomainError
+
+func NewError(kind error, code string, message string) *DomainError {
+	err := &DomainError{Kind: kind, Code: code, Message: message}
+	registered = append(registered, err)
+	return err
+}
+
+// Registered returns every error of the catalog, for checking that each code
+// has a translated message.
+func Registered() []*DomainError {
+	return registered
+}
+
+// Wrap returns a copy of e with cause attached.
+func (e *DomainError) Wrap(cause error) *DomainError {
+	copied := *e
+	copied.Err = cause
+	return &copied
+}
+
+// WithDetail returns a copy of e explaining what exactly was wrong.
+func (e *DomainError) WithDetail(format string, args ...interface{}) *DomainError {
+	copied := *e
+	copied.Detail = fmt.Sprintf(format, args...)
+	return &copied
+}
+
+func (e *DomainError) Error() string {
+	message := e.Message
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Is matches any DomainError with the same code, so wrapped copies still
+// satisfy errors.Is(err, ErrEmailTaken).
+func (e *DomainError) Is(target error) bool {
+	other, ok := target.(*DomainError)
+	return ok && other.Code == e.Code
+}
+
+func (e *DomainError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+var (
+	ErrInvalidID             = NewError(ErrBadRequest, "INVALID_ID", "invalid ID")
+	ErrInvalidRevision       = NewError(ErrBadRequest, "INVALID_REVISION", "invalid revision number")
+	ErrInvalidBody           = NewError(ErrBadRequest, "INVALID_BODY", "invalid request body")
+	ErrInvalidQuery          = NewError(ErrBadRequest, "INVALID_QUERY", "invalid query parameter")
+	ErrInvalidCursor         = NewError(ErrBadRequest, "INVALID_CURSOR", "invalid cursor")
+	ErrInvalidRole           = NewError(ErrBadRequest, "INVALID_ROLE", "unknown role")
+	ErrInvalidStatus         = NewError(ErrBadRequest, "INVALID_STATUS", "unknown account status")
+	ErrInvalidRef            = NewError(ErrBadRequest, "INVALID_REFERENCE", "referenced data does not exist")
+	ErrConstraint            = NewError(ErrBadRequest, "CONSTRAINT_VIOLATION", "data violates a database constraint")
+	ErrInvalidPatch          = NewError(ErrBadRequest, "INVALID_PATCH", "invalid patch document")
+	ErrInvalidIdempotencyKey = NewError(ErrBadRequest, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be 1 to 255 characters")
+	ErrInvalidReaction       = NewError(ErrBadRequest, "INVALID_REACTION", "unknown reaction type")
+	ErrSelfFollow            = NewError(ErrBadRequest, "SELF_FOLLOW", "users cannot follow themselves")
+
+	ErrInvalidCredentials = NewError(ErrUnauthorized, "INVALID_CREDENTIALS", "invalid credentials")
+	ErrInvalidToken       = NewError(ErrUnauthorized, "INVALID_TOKEN", "invalid or expired token")
+	ErrAuthRequired       = NewError(ErrUnauthorized, "AUTH_REQUIRED", "authentication required")
+
+	ErrAccountInactive = NewError(ErrForbidden, "ACCOUNT_INACTIVE", "account is not active")
+
+	ErrRecordNotFound = NewError(ErrNotFound, "NOT_FOUND", "data not found")
+	ErrUserNotFound   = NewError(ErrNotFound, "USER_NOT_FOUND", "user not found")
+
+	ErrEmailTaken            = NewError(ErrConflict, "EMAIL_TAKEN", "email is already registered")
+	ErrDuplicate             = NewError(ErrConflict, "DUPLICATE", "data already exists")
+	ErrConcurrentWrite       = NewError(ErrConflict, "CONCURRENT_UPDATE", "data was changed by another request, please retry")
+	ErrPatchTestFailed       = NewError(ErrConflict, "PATCH_TEST_FAILED", "a test operation of the patch failed")
+	ErrIdempotencyKeyReused  = NewError(ErrConflict, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used for a different request")
+	ErrIdempotencyInProgress = NewError(ErrConflict, "IDEMPOTENCY_IN_PROGRESS", "a request with this Idempotency-Key is still being processed")
+
+	ErrBatchTooLarge = NewError(ErrValidation, "BATCH_TOO_LARGE", "too many operations in one batch")
+
+	ErrVersionMismatch = NewError(ErrPrecondition, "VERSION_MISMATCH", "the data was changed since it was read, reload it and retry")
+
+	ErrUnsupportedPatch = NewError(ErrUnsupported, "UNSUPPORTED_PATCH", "patch must be application/merge-patch+json or application/json-patch+json")
+)
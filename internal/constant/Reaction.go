@@ -0,0 +1,27 @@
+package constant
+
+const (
+	ReactionLike  = "like"
+	ReactionLove  = "love"
+	ReactionLaugh = "laugh"
+	ReactionWow   = "wow"
+	ReactionSad   = "sad"
+	ReactionAngry = "angry"
+)
+
+var ReactionTypes = []string{ReactionLike, ReactionLove, ReactionLaugh, ReactionWow, ReactionSad, ReactionAngry}
+
+// ReactionEmoji is how clients should render each reaction type.
+var ReactionEmoji = map[string]string{
+	ReactionLike:  "👍",
+	ReactionLove:  "❤️",
+	ReactionLaugh: "😂",
+	ReactionWow:   "😮",
+	ReactionSad:   "😢",
+	ReactionAngry: "😠",
+}
+
+func IsValidReaction(reactionType string) bool {
+	_, ok := ReactionEmoji[reactionType]
+	return ok
+}
@@ -0,0 +1,54 @@
+package constant
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+)
+
+var Roles = []string{RoleAdmin, RoleModerator, RoleMember}
+
+type Permission string
+
+const (
+	PermManageUsers   Permission = "users:manage"
+	PermDeleteUser    Permission = "users:delete"
+	PermManageRoles   Permission = "roles:manage"
+	PermUpdateAnyPost Permission = "posts:update:any"
+	PermDeleteAnyPost Permission = "posts:delete:any"
+	PermTransferPost  Permission = "posts:transfer"
+
+	PermDeleteAnyComment Permission = "comments:delete:any"
+)
+
+// RolePermissions lists what each role may do beyond acting on its own data.
+var RolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermManageUsers,
+		PermDeleteUser,
+		PermManageRoles,
+		PermUpdateAnyPost,
+		PermDeleteAnyPost,
+		PermTransferPost,
+		PermDeleteAnyComment,
+	},
+	RoleModerator: {
+		PermDeleteAnyPost,
+		PermDeleteAnyComment,
+	},
+	RoleMember: {},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func HasPermission(role string, permission Permission) bool {
+	for _, granted := range RolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
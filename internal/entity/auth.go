@@ -0,0 +1,29 @@
+package entity
+
+import "User-Post-Backend/internal/constant"
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Actor is the authenticated caller attached to a request.
+type Actor struct {
+	UserID uint64 `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func (a Actor) Can(permission constant.Permission) bool {
+	return constant.HasPermission(a.Role, permission)
+}
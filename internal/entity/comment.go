@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment belongs to a post and, when it is a reply, to its parent comment.
+// Replies is only filled in the tree view.
+type Comment struct {
+	ID        uint64         `json:"id"`
+	PostID    uint64         `json:"post_id"`
+	ParentID  *uint64        `json:"parent_id"`
+	UserID    uint64         `json:"user_id"`
+	Content   string         `json:"content"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" swaggertype:"string" format:"date-time"`
+	Replies   []Comment      `json:"replies,omitempty" gorm:"-"`
+}
+
+const (
+	CommentViewTree = "tree"
+	CommentViewFlat = "flat"
+)
+
+type CreateComment struct {
+	ParentID *uint64 `json:"parent_id,omitempty"`
+	Content  string  `json:"content" validate:"required,max=5000"`
+}
+
+type UpdateComment struct {
+	Content string `json:"content" validate:"required,max=5000"`
+}
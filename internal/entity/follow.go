@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Follow is a user following another; the follower sees the posts of the
+// followee in their feed.
+type Follow struct {
+	FollowerID uint64    `json:"follower_id" gorm:"primaryKey"`
+	FolloweeID uint64    `json:"followee_id" gorm:"primaryKey"`
+	CreatedAt  time.Time `json:"created_at"`
+}
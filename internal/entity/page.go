@@ -0,0 +1,19 @@
+package entity
+
+type PageRequest struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor,omitempty"`
+
+	// Decoded from Cursor by the handler; zero when paging by offset.
+	CursorID uint64 `json:"-"`
+	Backward bool   `json:"-"`
+}
+
+type PageMeta struct {
+	Limit      int    `json:"limit"`
+	Offset     *int   `json:"offset,omitempty"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+// Patch is a raw PATCH body together with the media type telling how to
+// apply it.
+type Patch struct {
+	ContentType string
+	Document    []byte
+}
@@ -1,31 +1,85 @@
 package entity
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Post struct {
-	ID      uint64 `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	UserID  uint64 `json:"user_id"`
+	ID        uint64         `json:"id"`
+	Title     string         `json:"title"`
+	Content   string         `json:"content"`
+	UserID    uint64         `json:"user_id"`
+	Author    *User          `json:"author,omitempty" gorm:"foreignKey:UserID"`
+	Version   uint64         `json:"version" gorm:"default:1"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" swaggertype:"string" format:"date-time"`
+	Tags      []Tag          `json:"tags,omitempty" gorm:"many2many:post_tags"`
+	Reactions ReactionCounts `json:"reactions,omitempty" gorm:"-"`
+}
+
+// PostSearch is a full-text query over title and content, stemmed with the
+// Postgres text search configuration named by Language.
+type PostSearch struct {
+	Text     string
+	Language string
+}
+
+// PostSearchResult is a post matching a search, with its rank and an excerpt
+// where the matched words are wrapped in <mark> tags.
+type PostSearchResult struct {
+	Post
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// PostInclude selects related data to embed in post reads.
+type PostInclude struct {
+	Author bool
 }
 
+// Posts are also tagged with every #hashtag in their content, besides the
+// tags given explicitly. UserID defaults to the caller; only admins may
+// create posts for someone else.
 type CreatePost struct {
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
-	UserID  uint64 `json:"user_id" validate:"required"`
+	Title   string   `json:"title" validate:"required,max=255"`
+	Content string   `json:"content" validate:"required"`
+	UserID  uint64   `json:"user_id,omitempty"`
+	Tags    []string `json:"tags,omitempty" validate:"max=10,dive,tag"`
 }
 
+// UpdatePost replaces the explicit tags when Tags is set; an empty list
+// removes them.
 type UpdatePost struct {
-	ID      uint64  `json:"id" validate:"required"`
-	Title   *string `json:"title,omitempty"`
-	Content *string `json:"content,omitempty"`
-	UserID  *uint64 `json:"user_id,omitempty"`
+	ID      uint64    `json:"id" validate:"required"`
+	Title   *string   `json:"title,omitempty" validate:"omitnil,min=1,max=255"`
+	Content *string   `json:"content,omitempty" validate:"omitnil,min=1"`
+	Tags    *[]string `json:"tags,omitempty" validate:"omitnil,max=10,dive,tag"`
+}
+
+// PostPatch is the document a PATCH request edits; fields left out of it
+// cannot be patched.
+type PostPatch struct {
+	Title   string `json:"title" validate:"required,max=255"`
+	Content string `json:"content" validate:"required"`
+}
+
+type TransferPost struct {
+	UserID uint64 `json:"user_id" validate:"required"`
 }
 
 type MultiCreatePost struct {
-	UserID uint64  `json:"user_id" validate:"required"`
-	Posts  []Posts `json:"posts" validate:"required"`
+	UserID uint64  `json:"user_id,omitempty"`
+	Posts  []Posts `json:"posts" validate:"required,min=1,dive"`
 }
 
 type Posts struct {
-	Title   string `json:"title" validate:"required"`
+	Title   string `json:"title" validate:"required,max=255"`
 	Content string `json:"content" validate:"required"`
 }
+
+type CreatedPosts struct {
+	IDs []uint64 `json:"ids"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "best_effort"
+
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// PostBatch is a list of mixed post writes. In atomic mode, the default,
+// either every operation is applied or none; in best_effort mode each
+// operation succeeds or fails on its own. Creates are applied first, in one
+// bulk insert, then updates and deletes in the order given; operations can
+// only refer to posts that existed before the batch, so creates never depend
+// on that order.
+type PostBatch struct {
+	Mode       string               `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []PostBatchOperation `json:"operations" validate:"required,min=1"`
+}
+
+// PostBatchOperation creates a post from title and content, updates the title
+// and content of post ID, or deletes post ID. The author of a created post
+// defaults to the caller, and a non-zero version must match the stored one.
+type PostBatchOperation struct {
+	Op      string  `json:"op" validate:"required,oneof=create update delete"`
+	ID      uint64  `json:"id,omitempty" validate:"required_unless=Op create"`
+	Title   *string `json:"title,omitempty" validate:"required_if=Op create,omitnil,min=1,max=255"`
+	Content *string `json:"content,omitempty" validate:"required_if=Op create,omitnil,min=1"`
+	UserID  uint64  `json:"user_id,omitempty"`
+	Version uint64  `json:"version,omitempty"`
+}
+
+type PostBatchResult struct {
+	Mode      string          `json:"mode"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Items     []PostBatchItem `json:"items"`
+}
+
+// PostBatchItem is the outcome of the operation at Index. Err is set by the
+// usecase and turned into Status and Error by the handler.
+type PostBatchItem struct {
+	Index  int             `json:"index"`
+	Op     string          `json:"op"`
+	ID     uint64          `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Post   *Post           `json:"post,omitempty"`
+	Error  *BatchItemError `json:"error,omitempty"`
+	Err    error           `json:"-"`
+}
+
+type BatchItemError struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Errors  interface{} `json:"errors,omitempty" swaggertype:"array,object"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type PostRevision struct {
+	ID             uint64    `json:"id"`
+	PostID         uint64    `json:"post_id"`
+	Revision       int       `json:"revision"`
+	Title          string    `json:"title"`
+	Content        string    `json:"content"`
+	EditorID       *uint64   `json:"editor_id"`
+	RolledBackFrom *int      `json:"rolled_back_from,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff compares a revision with the current post; deletions are lines
+// only in the revision and insertions are lines only in the current post.
+type RevisionDiff struct {
+	PostRevision
+	TitleDiff   []DiffLine `json:"title_diff"`
+	ContentDiff []DiffLine `json:"content_diff"`
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	FilterEq       = "eq"
+	FilterContains = "contains"
+	FilterGt       = "gt"
+	FilterGte      = "gte"
+	FilterLt       = "lt"
+	FilterLte      = "lte"
+)
+
+const (
+	FieldNumber = "number"
+	FieldString = "string"
+	FieldTime   = "time"
+)
+
+// QueryField whitelists one column for filtering and sorting on a list
+// endpoint. Only columns named here ever reach SQL.
+type QueryField struct {
+	Column   string
+	Type     string
+	Ops      []string
+	Sortable bool
+}
+
+type QuerySpec map[string]QueryField
+
+var PostQuerySpec = QuerySpec{
+	"id":         {Column: "id", Type: FieldNumber, Ops: []string{FilterEq}, Sortable: true},
+	"user_id":    {Column: "user_id", Type: FieldNumber, Ops: []string{FilterEq}},
+	"title":      {Column: "title", Type: FieldString, Ops: []string{FilterEq, FilterContains}, Sortable: true},
+	"created_at": {Column: "created_at", Type: FieldTime, Ops: []string{FilterGt, FilterGte, FilterLt, FilterLte}, Sortable: true},
+}
+
+var UserQuerySpec = QuerySpec{
+	"id":         {Column: "id", Type: FieldNumber, Ops: []string{FilterEq}, Sortable: true},
+	"name":       {Column: "name", Type: FieldString, Ops: []string{FilterEq, FilterContains}, Sortable: true},
+	"created_at": {Column: "created_at", Type: FieldTime, Ops: []string{FilterGt, FilterGte, FilterLt, FilterLte}, Sortable: true},
+}
+
+type Filter struct {
+	Field  string
+	Column string
+	Op     string
+	Raw    string
+	Value  interface{}
+}
+
+type SortField struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+type ListQuery struct {
+	Filters []Filter
+	Sort    []SortField
+}
+
+// Key returns a normalized form of the query that is stable regardless of
+// the order the filters were sent in, for use in cache keys. Values are
+// escaped so a value containing "&" or "=" cannot pass for another filter.
+func (q ListQuery) Key() string {
+	filters := make([]string, 0, len(q.Filters))
+	for _, filter := range q.Filters {
+		filters = append(filters, filter.Field+"["+filter.Op+"]="+url.QueryEscape(filter.Raw))
+	}
+	sort.Strings(filters)
+
+	sorts := make([]string, 0, len(q.Sort))
+	for _, field := range q.Sort {
+		if field.Desc {
+			sorts = append(sorts, "-"+field.Field)
+		} else {
+			sorts = append(sorts, field.Field)
+		}
+	}
+	return "filter=" + strings.Join(filters, "&") + ":sort=" + strings.Join(sorts, ",")
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListQueryKey(t *testing.T) {
+	title := Filter{Field: "title", Op: FilterEq, Raw: "a"}
+	user := Filter{Field: "user_id", Op: FilterEq, Raw: "1"}
+
+	t.Run("ignores filter order", func(t *testing.T) {
+		a := ListQuery{Filters: []Filter{title, user}}
+		b := ListQuery{Filters: []Filter{user, title}}
+		assert.Equal(t, a.Key(), b.Key())
+	})
+
+	t.Run("keeps sort order", func(t *testing.T) {
+		a := ListQuery{Sort: []SortField{{Field: "title"}, {Field: "id", Desc: true}}}
+		b := ListQuery{Sort: []SortField{{Field: "id", Desc: true}, {Field: "title"}}}
+		assert.Equal(t, "filter=:sort=title,-id", a.Key())
+		assert.NotEqual(t, a.Key(), b.Key())
+	})
+
+	t.Run("escapes values", func(t *testing.T) {
+		smuggled := ListQuery{Filters: []Filter{{Field: "title", Op: FilterEq, Raw: "a&user_id[eq]=1"}}}
+		two := ListQuery{Filters: []Filter{title, user}}
+		assert.NotEqual(t, smuggled.Key(), two.Key())
+
+		colon := ListQuery{Filters: []Filter{{Field: "title", Op: FilterEq, Raw: "a:sort=id"}}}
+		sorted := ListQuery{Filters: []Filter{title}, Sort: []SortField{{Field: "id"}}}
+		assert.NotEqual(t, colon.Key(), sorted.Key())
+	})
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PostReaction is one reaction of a user to a post; a user holds at most one
+// of each type per post.
+type PostReaction struct {
+	PostID    uint64    `json:"post_id" gorm:"primaryKey"`
+	UserID    uint64    `json:"user_id" gorm:"primaryKey"`
+	Type      string    `json:"type" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionCounts maps each reaction type to how many users reacted with it.
+type ReactionCounts map[string]int64
+
+// Fingerprint is a short digest of the counts, so responses embedding them
+// can tell clients the counts changed.
+func (c ReactionCounts) Fingerprint() string {
+	types := make([]string, 0, len(c))
+	for reactionType := range c {
+		types = append(types, reactionType)
+	}
+	sort.Strings(types)
+
+	hash := fnv.New32a()
+	for _, reactionType := range types {
+		hash.Write([]byte(reactionType + "=" + strconv.FormatInt(c[reactionType], 10) + ";"))
+	}
+	return strconv.FormatUint(uint64(hash.Sum32()), 36)
+}
+
+type ReactionType struct {
+	Type  string `json:"type"`
+	Emoji string `json:"emoji"`
+}
+
+type ReconcileResult struct {
+	Posts    int `json:"posts"`
+	Repaired int `json:"repaired"`
+}
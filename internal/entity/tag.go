@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type Tag struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// PostTag links a post to a tag. FromContent marks tags that only come from
+// a #hashtag in the content; they follow the content when it changes, while
+// tags given explicitly stay until the tags are replaced.
+type PostTag struct {
+	PostID      uint64 `gorm:"primaryKey"`
+	TagID       uint64 `gorm:"primaryKey"`
+	FromContent bool
+}
+
+// TagCount is a tag with the number of posts using it.
+type TagCount struct {
+	Tag
+	Posts int64 `json:"posts"`
+}
@@ -1,10 +1,84 @@
 package entity
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type User struct {
-	ID   uint64 `json:"id"`
-	Name string `json:"name"`
+	ID           uint64         `json:"id"`
+	Name         string         `json:"name"`
+	Email        string         `json:"email,omitempty"`
+	Status       string         `json:"status,omitempty"`
+	Role         string         `json:"role,omitempty"`
+	PasswordHash string         `json:"-"`
+	Version      uint64         `json:"version" gorm:"default:1"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at" swaggertype:"string" format:"date-time"`
+}
+
+// Public returns the user as anyone may see it, without the email and the
+// account details reserved for the user and for user managers.
+func (u User) Public() User {
+	u.Email, u.Status, u.Role = "", "", ""
+	return u
+}
+
+// UserSuggestion is an autocomplete match for a user name, with its trigram
+// similarity to the typed text.
+type UserSuggestion struct {
+	ID         uint64  `json:"id"`
+	Name       string  `json:"name"`
+	Similarity float64 `json:"similarity"`
 }
 
 type CreateUser struct {
-	Name string `json:"name"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"omitempty,email,max=255"`
+	Password string `json:"password" validate:"omitempty,min=8,max_bytes=72"`
+}
+
+type UpdateUser struct {
+	Name  string `json:"name" validate:"omitempty,max=100"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+// UserPatch is the document a PATCH request edits; fields left out of it
+// cannot be patched.
+type UserPatch struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type ChangeRole struct {
+	Role string `json:"role" validate:"required,oneof=admin moderator member"`
+}
+
+type RoleInfo struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type ChangeStatus struct {
+	Status string `json:"status" validate:"required,oneof=active suspended deleted"`
+}
+
+type RegisterUser struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max_bytes=72"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max_bytes=72"`
+}
+
+type PurgeResult struct {
+	Before   time.Time `json:"before"`
+	Comments int64     `json:"comments"`
+	Posts    int64     `json:"posts"`
+	Users    int64     `json:"users"`
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
+	"User-Post-Backend/internal/repository"
+	"User-Post-Backend/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type AdminHandler struct {
+	userUsecase  usecase.UserUsecase
+	adminUsecase usecase.AdminUsecase
+}
+
+func NewAdminHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient) {
+	userRepo := repository.NewUserRepository(db)
+	postRepo := repository.NewPostRepository(db, infra.EnvInt("POST_INSERT_BATCH_SIZE", 100))
+	commentRepo := repository.NewCommentRepository(db)
+	retention := infra.EnvDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour)
+	handler := &AdminHandler{
+		userUsecase:  usecase.NewUserUsecase(userRepo, cache),
+		adminUsecase: usecase.NewAdminUsecase(userRepo, postRepo, commentRepo, retention),
+	}
+
+	admin := app.Group("/api/v1/admin")
+
+	admin.Get("/roles", middleware.Authorize(constant.PermManageRoles), handler.GetRoles)
+	admin.Put("/users/:id/role", middleware.Authorize(constant.PermManageRoles), handler.ChangeRole)
+	admin.Put("/users/:id/status", middleware.Authorize(constant.PermManageUsers), handler.ChangeStatus)
+	admin.Post("/purge", middleware.Authorize(constant.PermManageUsers), handler.Purge)
+}
+
+// @Summary List roles
+// @Description List every role with the permissions it grants
+// @Tags admin
+// @Produce  json
+// @Success 200 {array} entity.RoleInfo
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Security BearerAuth
+// @Router /api/v1/admin/roles [get]
+func (h *AdminHandler) GetRoles(c *fiber.Ctx) error {
+	roles := make([]entity.RoleInfo, 0, len(constant.Roles))
+	for _, role := range constant.Roles {
+		permissions := make([]string, 0, len(constant.RolePermissions[role]))
+		for _, permission := range constant.RolePermissions[role] {
+			permissions = append(permissions, string(permission))
+		}
+		roles = append(roles, entity.RoleInfo{Role: role, Permissions: permissions})
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "role.listed", roles)
+}
+
+// @Summary Change a user's role
+// @Description Assign the admin, moderator or member role to a user
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param role body entity.ChangeRole true "New role"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} helpers.StandardResponse "Unknown role"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	var req entity.ChangeRole
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+
+	user, err := h.userUsecase.ChangeRole(id, req)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "user.role_changed", user)
+}
+
+// @Summary Change a user's account status
+// @Description Activate, suspend or mark a user account as deleted
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param status body entity.ChangeStatus true "New status"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} helpers.StandardResponse "Unknown status"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/admin/users/{id}/status [put]
+func (h *AdminHandler) ChangeStatus(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	var req entity.ChangeStatus
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+
+	user, err := h.userUsecase.ChangeStatus(id, req)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "user.status_changed", user)
+}
+
+// @Summary Purge soft-deleted rows
+// @Description Hard-delete users, posts and comments soft-deleted longer ago than SOFT_DELETE_RETENTION
+// @Tags admin
+// @Produce  json
+// @Success 200 {object} entity.PurgeResult
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Security BearerAuth
+// @Router /api/v1/admin/purge [post]
+func (h *AdminHandler) Purge(c *fiber.Ctx) error {
+	result, err := h.adminUsecase.Purge()
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "admin.purged", result)
+}
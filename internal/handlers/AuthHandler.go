@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/repository"
+	"User-Post-Backend/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type AuthHandler struct {
+	authUsecase usecase.AuthUsecase
+}
+
+func NewAuthHandler(app *fiber.App, db *gorm.DB, tokens *infra.JWTManager) {
+	repo := repository.NewUserRepository(db)
+	usecase := usecase.NewAuthUsecase(repo, tokens)
+	handler := &AuthHandler{authUsecase: usecase}
+
+	apiv1 := app.Group("/api/v1")
+
+	apiv1.Post("/auth/login", handler.Login)
+	apiv1.Post("/auth/refresh", handler.Refresh)
+}
+
+// @Summary Log in
+// @Description Exchange user credentials for an access and refresh token
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param credentials body entity.LoginRequest true "User credentials"
+// @Success 200 {object} entity.TokenPair
+// @Failure 401 {object} helpers.StandardResponse "Invalid credentials"
+// @Failure 403 {object} helpers.StandardResponse "Account is not active"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req entity.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+	tokens, err := h.authUsecase.Login(req)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "auth.logged_in", tokens)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access and refresh token
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param token body entity.RefreshRequest true "Refresh token"
+// @Success 200 {object} entity.TokenPair
+// @Failure 401 {object} helpers.StandardResponse "Invalid or expired token"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req entity.RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+	tokens, err := h.authUsecase.Refresh(req)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "auth.refreshed", tokens)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
+	"User-Post-Backend/internal/repository"
+	"User-Post-Backend/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	commentUsecase usecase.CommentUsecase
+}
+
+func NewCommentHandler(app *fiber.App, db *gorm.DB) {
+	repo := repository.NewCommentRepository(db)
+	usecase := usecase.NewCommentUsecase(repo)
+	handler := &CommentHandler{commentUsecase: usecase}
+
+	apiv1 := app.Group("/api/v1")
+
+	apiv1.Get("/posts/:id/comments", handler.GetByPost)
+	apiv1.Post("/posts/:id/comments", handler.Create)
+	apiv1.Get("/comments/:id", handler.GetByID)
+	apiv1.Put("/comments/:id", handler.Update)
+	apiv1.Delete("/comments/:id", handler.Delete)
+}
+
+// @Summary List comments of a post
+// @Description Get a page of top level comments with their replies nested below them (tree, the default), or a page of all comments newest first (flat)
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param view query string false "tree or flat"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Success 200 {array} entity.Comment
+// @Failure 400 {object} helpers.StandardResponse "Invalid view or cursor"
+// @Failure 404 {object} helpers.StandardResponse "Post not found"
+// @Router /api/v1/posts/{id}/comments [get]
+func (h *CommentHandler) GetByPost(c *fiber.Ctx) error {
+	postID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	view := c.Query("view", entity.CommentViewTree)
+	if view != entity.CommentViewTree && view != entity.CommentViewFlat {
+		return constant.ErrInvalidQuery.WithDetail("view must be tree or flat")
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+
+	comments, meta, err := h.commentUsecase.GetByPost(postID, view, page)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "comment.listed", comments, meta)
+}
+
+// @Summary Comment on a post
+// @Description Add a comment to a post, or a reply to one of its comments
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param comment body entity.CreateComment true "Comment data"
+// @Success 201 {object} entity.Comment
+// @Header 201 {string} Location "URL of the new comment"
+// @Failure 400 {object} helpers.StandardResponse "Parent comment not found or of another post"
+// @Failure 404 {object} helpers.StandardResponse "Post not found"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/comments [post]
+func (h *CommentHandler) Create(c *fiber.Ctx) error {
+	postID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	var comment entity.CreateComment
+	if err := c.BodyParser(&comment); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(comment); err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	created, err := h.commentUsecase.Create(actor, postID, comment)
+	if err != nil {
+		return err
+	}
+	c.Location("/api/v1/comments/" + strconv.FormatUint(created.ID, 10))
+	return helpers.SendResponse(c, fiber.StatusCreated, "comment.created", created)
+}
+
+// @Summary Get a comment by ID
+// @Description Get a single comment without its replies
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Success 200 {object} entity.Comment
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Router /api/v1/comments/{id} [get]
+func (h *CommentHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	comment, err := h.commentUsecase.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "comment.retrieved", comment)
+}
+
+// @Summary Edit a comment
+// @Description Change the content of a comment; only its author may
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Param comment body entity.UpdateComment true "Comment data"
+// @Success 200 {object} entity.Comment
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/comments/{id} [put]
+func (h *CommentHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	var comment entity.UpdateComment
+	if err := c.BodyParser(&comment); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(comment); err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	updated, err := h.commentUsecase.Update(actor, id, comment)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "comment.updated", updated)
+}
+
+// @Summary Delete a comment
+// @Description Delete a comment together with all replies below it
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Comment ID"
+// @Success 200 {string} string "Comment deleted"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Security BearerAuth
+// @Router /api/v1/comments/{id} [delete]
+func (h *CommentHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	if err := h.commentUsecase.Delete(actor, id); err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "comment.deleted", nil)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
+	"User-Post-Backend/internal/repository"
+	"User-Post-Backend/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type FollowHandler struct {
+	followUsecase usecase.FollowUsecase
+}
+
+func NewFollowHandler(app *fiber.App, db *gorm.DB) {
+	repo := repository.NewFollowRepository(db)
+	postRepo := repository.NewPostRepository(db, infra.EnvInt("POST_INSERT_BATCH_SIZE", 100))
+	usecase := usecase.NewFollowUsecase(repo, usecase.NewFanOutOnReadFeed(postRepo))
+	handler := &FollowHandler{followUsecase: usecase}
+
+	apiv1 := app.Group("/api/v1")
+
+	apiv1.Get("/feed", middleware.RequireAuth(), handler.Feed)
+	apiv1.Post("/users/:id/follow", handler.Follow)
+	apiv1.Delete("/users/:id/follow", handler.Unfollow)
+	apiv1.Get("/users/:id/followers", handler.GetFollowers)
+	apiv1.Get("/users/:id/following", handler.GetFollowing)
+}
+
+// @Summary Get the home feed
+// @Description Get a page of the posts of every user the caller follows, newest first
+// @Tags follows
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param include query string false "Set to author to embed the author of each post"
+// @Success 200 {array} entity.Post
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor"
+// @Failure 401 {object} helpers.StandardResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/feed [get]
+func (h *FollowHandler) Feed(c *fiber.Ctx) error {
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	include, err := parsePostInclude(c)
+	if err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	posts, meta, err := h.followUsecase.Feed(actor, page, include)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "feed.retrieved", visibleAuthors(c, posts), meta)
+}
+
+// @Summary Follow a user
+// @Description Add the posts of a user to the feed of the caller; following a user again changes nothing
+// @Tags follows
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {string} string "User followed"
+// @Failure 400 {object} helpers.StandardResponse "Cannot follow yourself"
+// @Failure 404 {object} helpers.StandardResponse "User not found"
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/follow [post]
+func (h *FollowHandler) Follow(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	if err := h.followUsecase.Follow(actor, id); err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "follow.followed", nil)
+}
+
+// @Summary Unfollow a user
+// @Description Remove the posts of a user from the feed of the caller
+// @Tags follows
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {string} string "User unfollowed"
+// @Failure 400 {object} helpers.StandardResponse "Cannot follow yourself"
+// @Failure 404 {object} helpers.StandardResponse "User not found"
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/follow [delete]
+func (h *FollowHandler) Unfollow(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	if err := h.followUsecase.Unfollow(actor, id); err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "follow.unfollowed", nil)
+}
+
+// @Summary List followers
+// @Description Get a page of the users following a user
+// @Tags follows
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Success 200 {array} entity.User
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor"
+// @Failure 404 {object} helpers.StandardResponse "User not found"
+// @Router /api/v1/users/{id}/followers [get]
+func (h *FollowHandler) GetFollowers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	users, meta, err := h.followUsecase.GetFollowers(id, page)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "follow.followers", visibleUsers(c, users), meta)
+}
+
+// @Summary List followed users
+// @Description Get a page of the users a user follows
+// @Tags follows
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Success 200 {array} entity.User
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor"
+// @Failure 404 {object} helpers.StandardResponse "User not found"
+// @Router /api/v1/users/{id}/following [get]
+func (h *FollowHandler) GetFollowing(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	users, meta, err := h.followUsecase.GetFollowing(id, page)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "follow.following", visibleUsers(c, users), meta)
+}
@@ -2,33 +2,66 @@ package handlers
 
 import (
 	"User-Post-Backend/infra"
+	"User-Post-Backend/infra/logger"
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
 	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
 	"User-Post-Backend/internal/repository"
 	"User-Post-Backend/internal/usecase"
+	"errors"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
 type PostHandler struct {
-	postUsecase usecase.PostUsecase
+	postUsecase     usecase.PostUsecase
+	reactionUsecase usecase.ReactionUsecase
+	searchLanguage  string
 }
 
-func NewPostHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient) {
-	repo := repository.NewPostRepository(db)
-	usecase := usecase.NewPostUsecase(repo, cache)
-	handler := &PostHandler{postUsecase: usecase}
+// NewPostHandler searches with searchLanguage, the text search configuration
+// applied to the posts table at startup.
+func NewPostHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient, searchLanguage string) {
+	repo := repository.NewPostRepository(db, infra.EnvInt("POST_INSERT_BATCH_SIZE", 100))
+	revisionRepo := repository.NewPostRevisionRepository(db)
+	reactionUsecase := usecase.NewReactionUsecase(repository.NewReactionRepository(db), cache)
+	tagRepo := repository.NewTagRepository(db)
+	usecase := usecase.NewPostUsecase(repo, revisionRepo, tagRepo, repository.NewUserRepository(db), cache, infra.EnvInt("POST_BATCH_MAX_SIZE", 500))
+	handler := &PostHandler{
+		postUsecase:     usecase,
+		reactionUsecase: reactionUsecase,
+		searchLanguage:  searchLanguage,
+	}
+
+	// Only post creation is made retry safe; other POST routes, such as the
+	// auth ones, must never have their responses stored.
+	idempotent := middleware.Idempotency(cache, infra.EnvDuration("IDEMPOTENCY_WINDOW", 24*time.Hour))
 
 	apiv1 := app.Group("/api/v1")
 
-	apiv1.Post("/posts", handler.Create)
-	app.Post("/multi-posts", handler.CreateMultiplePosts)
+	apiv1.Post("/posts", idempotent, handler.Create)
+	apiv1.Post("/multi-posts", idempotent, handler.CreateMultiplePosts)
+	apiv1.Post("/posts\\:batch", idempotent, handler.Batch)
 	apiv1.Get("/posts", handler.GetAll)
+	apiv1.Get("/posts/search", handler.Search)
 	apiv1.Get("/posts/:id", handler.GetByID)
+	apiv1.Get("/users/:id/posts", handler.GetByUser)
+	apiv1.Get("/tags", handler.GetTags)
+	apiv1.Get("/tags/:name/posts", handler.GetByTag)
 	apiv1.Put("/posts/:id", handler.Update)
+	apiv1.Patch("/posts/:id", handler.UpdatePatch)
+	apiv1.Put("/posts/:id/owner", middleware.Authorize(constant.PermTransferPost), handler.Transfer)
 	apiv1.Delete("/posts/:id", handler.Delete)
+	apiv1.Post("/posts/:id/restore", handler.Restore)
+	apiv1.Get("/posts/:id/revisions", handler.GetRevisions)
+	apiv1.Get("/posts/:id/revisions/:revision", handler.GetRevision)
+	apiv1.Post("/posts/:id/revisions/:revision/rollback", handler.Rollback)
 }
 
 // @Summary Get all posts
@@ -36,34 +69,213 @@ func NewPostHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient) {
 // @Tags posts
 // @Accept  json
 // @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param user_id query int false "Filter by author"
+// @Param title query string false "Exact title; use title[contains] for a substring match"
+// @Param created_at[gte] query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_at[lte] query string false "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "Comma separated id, title, created_at; prefix with - for descending"
+// @Param include query string false "Set to author to embed the author of each post"
 // @Success 200 {array} entity.Post
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor, filter or sort"
 // @Router /api/v1/posts [get]
 func (h *PostHandler) GetAll(c *fiber.Ctx) error {
-	posts, err := h.postUsecase.GetAll()
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	query, err := helpers.ParseListQuery(c, entity.PostQuerySpec)
+	if err != nil {
+		return err
+	}
+	include, err := parsePostInclude(c)
+	if err != nil {
+		return err
+	}
+	posts, meta, err := h.postUsecase.GetAll(page, query, include)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "post.listed", visibleAuthors(c, posts), meta)
+}
+
+// @Summary Search posts
+// @Description Full-text search over title and content, best match first. The snippet is HTML-escaped content with the matched words wrapped in <mark> tags.
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search text; quoted phrases, or and -excluded words work as in web search"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip"
+// @Success 200 {array} entity.PostSearchResult
+// @Failure 400 {object} helpers.StandardResponse "Missing search text or a cursor was given"
+// @Router /api/v1/posts/search [get]
+func (h *PostHandler) Search(c *fiber.Ctx) error {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		return constant.ErrInvalidQuery.WithDetail("q is required")
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	if page.Cursor != "" {
+		return constant.ErrInvalidQuery.WithDetail("search results are paged by offset, not by cursor")
+	}
+
+	search := entity.PostSearch{Text: text, Language: h.searchLanguage}
+	results, meta, err := h.postUsecase.Search(search, page)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "successfully retrieved posts", posts)
+	return helpers.SendPageResponse(c, fiber.StatusOK, "post.searched", results, meta)
+}
+
+// @Summary Get posts of a user
+// @Description Get a page of the posts written by one user
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param title query string false "Exact title; use title[contains] for a substring match"
+// @Param sort query string false "Comma separated id, title, created_at; prefix with - for descending"
+// @Param include query string false "Set to author to embed the author of each post"
+// @Success 200 {array} entity.Post
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor, filter or sort"
+// @Failure 404 {object} helpers.StandardResponse "User not found"
+// @Router /api/v1/users/{id}/posts [get]
+func (h *PostHandler) GetByUser(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	query, err := helpers.ParseListQuery(c, entity.PostQuerySpec)
+	if err != nil {
+		return err
+	}
+	include, err := parsePostInclude(c)
+	if err != nil {
+		return err
+	}
+	posts, meta, err := h.postUsecase.GetByUser(userID, page, query, include)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "post.listed", visibleAuthors(c, posts), meta)
+}
+
+// @Summary List tags
+// @Description Get a page of the tags in use with the number of posts tagged with each, most used first
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip"
+// @Success 200 {array} entity.TagCount
+// @Failure 400 {object} helpers.StandardResponse "Invalid paging"
+// @Router /api/v1/tags [get]
+func (h *PostHandler) GetTags(c *fiber.Ctx) error {
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	if page.Cursor != "" {
+		return constant.ErrInvalidQuery.WithDetail("tags are paged by offset, not by cursor")
+	}
+
+	tags, meta, err := h.postUsecase.GetTags(page)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "tag.listed", tags, meta)
+}
+
+// @Summary Get posts with a tag
+// @Description Get a page of the posts tagged with a tag, explicitly or by a hashtag in their content
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param name path string true "Tag name, with or without a leading %23"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param title query string false "Exact title; use title[contains] for a substring match"
+// @Param sort query string false "Comma separated id, title, created_at; prefix with - for descending"
+// @Param include query string false "Set to author to embed the author of each post"
+// @Success 200 {array} entity.Post
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor, filter or sort"
+// @Failure 404 {object} helpers.StandardResponse "Tag not found"
+// @Router /api/v1/tags/{name}/posts [get]
+func (h *PostHandler) GetByTag(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return constant.ErrRecordNotFound
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	query, err := helpers.ParseListQuery(c, entity.PostQuerySpec)
+	if err != nil {
+		return err
+	}
+	include, err := parsePostInclude(c)
+	if err != nil {
+		return err
+	}
+	posts, meta, err := h.postUsecase.GetByTag(name, page, query, include)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "tag.posts_listed", visibleAuthors(c, posts), meta)
 }
 
 // @Summary Get a post by ID
-// @Description Get a single post by ID
+// @Description Get a single post by ID with its reaction counts
 // @Tags posts
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Post ID"
+// @Param include query string false "Set to author to embed the author"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} entity.Post
+// @Success 304 "Not modified"
+// @Header 200 {string} ETag "Version of the post and its reaction counts"
 // @Router /api/v1/posts/{id} [get]
 func (h *PostHandler) GetByID(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
+	}
+	include, err := parsePostInclude(c)
+	if err != nil {
+		return err
+	}
+	post, err := h.postUsecase.GetByID(id, include)
+	if err != nil {
+		return err
 	}
-	post, err := h.postUsecase.GetByID(id)
+	post.Reactions, err = h.reactionUsecase.Counts(id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "successfully retrieved post", post)
+	if helpers.NotModified(c, post.Version, post.Reactions.Fingerprint()) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	if post.Author != nil {
+		author := visibleUser(c, *post.Author)
+		post.Author = &author
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "post.retrieved", post)
 }
 
 // @Summary Create a new post
@@ -72,17 +284,29 @@ func (h *PostHandler) GetByID(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param post body entity.CreatePost true "Post data"
-// @Success 201 {string} string "Post created"
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the first response"
+// @Success 201 {object} entity.Post
+// @Header 201 {string} Location "URL of the new post"
+// @Failure 403 {object} helpers.StandardResponse "Only admins may create posts for another user"
+// @Failure 409 {object} helpers.StandardResponse "The Idempotency-Key was reused for a different request"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
 // @Router /api/v1/posts [post]
 func (h *PostHandler) Create(c *fiber.Ctx) error {
 	var post entity.CreatePost
 	if err := c.BodyParser(&post); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return constant.ErrInvalidBody.Wrap(err)
 	}
-	if err := h.postUsecase.Create(post); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	if err := helpers.Validate(post); err != nil {
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusCreated, "post created successfully", nil)
+	actor, _ := middleware.CurrentActor(c)
+	created, err := h.postUsecase.Create(actor, post)
+	if err != nil {
+		return err
+	}
+	c.Location("/api/v1/posts/" + strconv.FormatUint(created.ID, 10))
+	return helpers.SendResponse(c, fiber.StatusCreated, "post.created", created)
 }
 
 // @Summary Create a new multi post
@@ -91,19 +315,97 @@ func (h *PostHandler) Create(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param post body entity.MultiCreatePost true "Post data"
-// @Success 201 {string} string "Post created"
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the first response"
+// @Success 201 {object} entity.CreatedPosts
+// @Failure 403 {object} helpers.StandardResponse "Only admins may create posts for another user"
+// @Failure 409 {object} helpers.StandardResponse "The Idempotency-Key was reused for a different request"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed or more posts than POST_BATCH_MAX_SIZE"
+// @Security BearerAuth
 // @Router /api/v1/multi-posts [post]
 func (h *PostHandler) CreateMultiplePosts(c *fiber.Ctx) error {
 	var multiCreatePost entity.MultiCreatePost
 	if err := c.BodyParser(&multiCreatePost); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid input"})
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(multiCreatePost); err != nil {
+		return err
 	}
 
-	if err := h.postUsecase.CreateMultiplePosts(multiCreatePost); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create posts"})
+	actor, _ := middleware.CurrentActor(c)
+	posts, err := h.postUsecase.CreateMultiplePosts(actor, multiCreatePost)
+	if err != nil {
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Posts created successfully"})
+	created := entity.CreatedPosts{IDs: make([]uint64, 0, len(posts))}
+	for _, post := range posts {
+		created.IDs = append(created.IDs, post.ID)
+	}
+	return helpers.SendResponse(c, fiber.StatusCreated, "post.bulk_created", created)
+}
+
+// @Summary Apply a batch of post writes
+// @Description Create, update and delete posts in one request. In atomic mode (the default) the batch fails as a whole at the first failing operation and nothing is written; in best_effort mode every operation is reported with its own status and 207 is returned when any failed. Creates are applied first, then updates and deletes in the order given; updates and deletes can only refer to posts that existed before the batch. Results are listed in the order of the operations.
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param batch body entity.PostBatch true "Operations"
+// @Param Idempotency-Key header string false "Key that makes retries of this request replay the first response"
+// @Success 200 {object} entity.PostBatchResult
+// @Success 207 {object} entity.PostBatchResult "Some operations failed in best_effort mode"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Failure 412 {object} helpers.StandardResponse "A post changed since it was read"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed or too many operations"
+// @Security BearerAuth
+// @Router /api/v1/posts:batch [post]
+func (h *PostHandler) Batch(c *fiber.Ctx) error {
+	var batch entity.PostBatch
+	if err := c.BodyParser(&batch); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(batch); err != nil {
+		return err
+	}
+
+	actor, _ := middleware.CurrentActor(c)
+	result, err := h.postUsecase.Batch(actor, batch)
+	if err != nil {
+		return err
+	}
+
+	language := helpers.Language(c)
+	for i := range result.Items {
+		item := &result.Items[i]
+		switch {
+		case item.Err != nil:
+			item.Status, item.Error = batchItemError(item.Err, language)
+		case item.Op == entity.BatchCreate:
+			item.Status = fiber.StatusCreated
+		default:
+			item.Status = fiber.StatusOK
+		}
+	}
+	status := fiber.StatusOK
+	if result.Failed > 0 {
+		status = fiber.StatusMultiStatus
+	}
+	return helpers.SendResponse(c, status, "post.batch_applied", result)
+}
+
+// batchItemError reports a failed batch operation the way the error
+// middleware would report it as a whole response.
+func batchItemError(err error, language string) (int, *entity.BatchItemError) {
+	status, appError := middleware.ResolveError(err, language)
+	itemError := &entity.BatchItemError{Code: appError.Code, Message: appError.Message}
+	var validationError *helpers.ValidationError
+	if errors.As(err, &validationError) {
+		itemError.Errors = validationError.Localize(language)
+	}
+	if status >= fiber.StatusInternalServerError {
+		logger.Errorln(err)
+	}
+	return status, itemError
 }
 
 // @Summary Update an existing post
@@ -112,55 +414,127 @@ func (h *PostHandler) CreateMultiplePosts(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Post ID"
-// @Param post body entity.Post true "Post data"
-// @Success 200 {string} string "Post updated"
+// @Param post body entity.UpdatePost true "Post data"
+// @Param If-Match header string false "ETag the update is based on"
+// @Success 200 {object} entity.Post
+// @Header 200 {string} ETag "New version of the post"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 412 {object} helpers.StandardResponse "The post changed since it was read"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
 // @Router /api/v1/posts/{id} [put]
 func (h *PostHandler) Update(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
+	}
+
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
 	}
 
 	var post entity.UpdatePost
 	if err := c.BodyParser(&post); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return constant.ErrInvalidBody.Wrap(err)
 	}
 	post.ID = id
-	if err := h.postUsecase.Update(post); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	if err := helpers.Validate(post); err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	updated, err := h.postUsecase.Update(actor, post, version)
+	if err != nil {
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "post updated successfully", nil)
+	helpers.SetETag(c, updated.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "post.updated", updated)
 }
 
-// @Summary Update an existing post
-// @Description Update a post's data
+// @Summary Transfer a post
+// @Description Move a post to another user (admin only)
 // @Tags posts
-// @Accept json
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param owner body entity.TransferPost true "New owner"
+// @Param If-Match header string false "ETag the transfer is based on"
+// @Success 200 {object} entity.Post
+// @Header 200 {string} ETag "New version of the post"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Post or new owner not found"
+// @Failure 412 {object} helpers.StandardResponse "The post changed since it was read"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/owner [put]
+func (h *PostHandler) Transfer(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
+	}
+
+	var req entity.TransferPost
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	post, err := h.postUsecase.Transfer(actor, id, req, version)
+	if err != nil {
+		return err
+	}
+	helpers.SetETag(c, post.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "post.transferred", post)
+}
+
+// @Summary Partially update a post
+// @Description Apply a JSON Merge Patch (RFC 7386) or JSON Patch (RFC 6902) to the title and content of a post
+// @Tags posts
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
 // @Produce json
 // @Param id path int true "Post ID"
-// @Param post body entity.UpdatePost true "Post data"
-// @Success 200 {object} helpers.StandardResponse "Post updated successfully"
-// @Failure 400 {object} helpers.StandardResponse "Invalid ID or request body"
+// @Param patch body entity.PostPatch true "Merge patch, or an array of JSON Patch operations"
+// @Param If-Match header string false "ETag the patch is based on"
+// @Success 200 {object} entity.Post
+// @Header 200 {string} ETag "New version of the post"
+// @Failure 400 {object} helpers.StandardResponse "Invalid ID or patch document"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
 // @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Failure 409 {object} helpers.StandardResponse "A test operation failed"
+// @Failure 412 {object} helpers.StandardResponse "The post changed since it was read"
+// @Failure 415 {object} helpers.StandardResponse "Unsupported patch media type"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
 // @Router /api/v1/posts/{id} [patch]
 func (h *PostHandler) UpdatePatch(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
 	}
 
-	var req entity.UpdatePost
-	if err := c.BodyParser(&req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
 	}
-
-	req.ID = id
-	err = h.postUsecase.Update(req)
+	patch, err := helpers.ParsePatch(c)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
-
-	return helpers.SendResponse(c, fiber.StatusOK, "Post updated successfully", nil)
+	actor, _ := middleware.CurrentActor(c)
+	updated, err := h.postUsecase.Patch(actor, id, patch, version)
+	if err != nil {
+		return err
+	}
+	helpers.SetETag(c, updated.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "post.updated", updated)
 }
 
 // @Summary Delete a post
@@ -169,15 +543,139 @@ func (h *PostHandler) UpdatePatch(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Post ID"
+// @Param If-Match header string false "ETag the deletion is based on"
 // @Success 200 {string} string "Post deleted"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 412 {object} helpers.StandardResponse "The post changed since it was read"
+// @Security BearerAuth
 // @Router /api/v1/posts/{id} [delete]
 func (h *PostHandler) Delete(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
+	}
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
+	}
+	actor, _ := middleware.CurrentActor(c)
+	if err := h.postUsecase.Delete(actor, id, version); err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "post.deleted", nil)
+}
+
+// @Summary Restore a post
+// @Description Restore a soft-deleted post
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Success 200 {string} string "Post restored"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/restore [post]
+func (h *PostHandler) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	if err := h.postUsecase.Restore(actor, id); err != nil {
+		return err
 	}
-	if err := h.postUsecase.Delete(id); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	return helpers.SendResponse(c, fiber.StatusOK, "post.restored", nil)
+}
+
+// @Summary List post revisions
+// @Description Get a page of the recorded revisions of a post, newest first
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Success 200 {array} entity.PostRevision
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Router /api/v1/posts/{id}/revisions [get]
+func (h *PostHandler) GetRevisions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	page, err := helpers.ParsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	revisions, meta, err := h.postUsecase.GetRevisions(id, page)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "revision.listed", revisions, meta)
+}
+
+// @Summary Get a post revision
+// @Description Get one revision with a line diff against the current post
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Router /api/v1/posts/{id}/revisions/{revision} [get]
+func (h *PostHandler) GetRevision(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil {
+		return constant.ErrInvalidRevision
+	}
+	diff, err := h.postUsecase.GetRevision(id, revision)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "revision.retrieved", diff)
+}
+
+// @Summary Roll back a post
+// @Description Restore the title and content of a revision, recorded as a new revision
+// @Tags posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} entity.Post
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/revisions/{revision}/rollback [post]
+func (h *PostHandler) Rollback(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil {
+		return constant.ErrInvalidRevision
+	}
+	actor, _ := middleware.CurrentActor(c)
+	post, err := h.postUsecase.Rollback(actor, id, revision)
+	if err != nil {
+		return err
+	}
+	helpers.SetETag(c, post.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "post.rolled_back", post)
+}
+
+func parsePostInclude(c *fiber.Ctx) (entity.PostInclude, error) {
+	include, err := helpers.ParseInclude(c, "author")
+	if err != nil {
+		return entity.PostInclude{}, err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "post deleted successfully", nil)
+	return entity.PostInclude{Author: include["author"]}, nil
 }
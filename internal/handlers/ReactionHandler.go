@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
+	"User-Post-Backend/internal/repository"
+	"User-Post-Backend/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ReactionHandler struct {
+	reactionUsecase usecase.ReactionUsecase
+}
+
+func NewReactionHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient) {
+	repo := repository.NewReactionRepository(db)
+	usecase := usecase.NewReactionUsecase(repo, cache)
+	handler := &ReactionHandler{reactionUsecase: usecase}
+
+	infra.Every(infra.EnvDuration("REACTION_RECONCILE_INTERVAL", 10*time.Minute), "reconcile reactions", func() error {
+		_, err := usecase.Reconcile()
+		return err
+	})
+
+	apiv1 := app.Group("/api/v1")
+
+	apiv1.Get("/reactions", handler.GetTypes)
+	apiv1.Put("/posts/:id/reactions/:type", handler.React)
+	apiv1.Delete("/posts/:id/reactions/:type", handler.Unreact)
+	apiv1.Post("/admin/reactions/reconcile", middleware.Authorize(constant.PermManageUsers), handler.Reconcile)
+}
+
+// @Summary List reaction types
+// @Description List the reactions a post accepts with the emoji for each
+// @Tags reactions
+// @Produce  json
+// @Success 200 {array} entity.ReactionType
+// @Router /api/v1/reactions [get]
+func (h *ReactionHandler) GetTypes(c *fiber.Ctx) error {
+	types := make([]entity.ReactionType, len(constant.ReactionTypes))
+	for i, reactionType := range constant.ReactionTypes {
+		types[i] = entity.ReactionType{Type: reactionType, Emoji: constant.ReactionEmoji[reactionType]}
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "reaction.listed", types)
+}
+
+// @Summary React to a post
+// @Description Add a reaction of the caller to a post; reacting again with the same type changes nothing
+// @Tags reactions
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param type path string true "Reaction type"
+// @Success 200 {object} entity.ReactionCounts
+// @Failure 400 {object} helpers.StandardResponse "Unknown reaction type"
+// @Failure 404 {object} helpers.StandardResponse "Post not found"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/reactions/{type} [put]
+func (h *ReactionHandler) React(c *fiber.Ctx) error {
+	postID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	counts, err := h.reactionUsecase.React(actor, postID, c.Params("type"))
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "reaction.added", counts)
+}
+
+// @Summary Remove a reaction
+// @Description Remove a reaction of the caller from a post
+// @Tags reactions
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param type path string true "Reaction type"
+// @Success 200 {object} entity.ReactionCounts
+// @Failure 400 {object} helpers.StandardResponse "Unknown reaction type"
+// @Failure 404 {object} helpers.StandardResponse "Post not found"
+// @Security BearerAuth
+// @Router /api/v1/posts/{id}/reactions/{type} [delete]
+func (h *ReactionHandler) Unreact(c *fiber.Ctx) error {
+	postID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+	actor, _ := middleware.CurrentActor(c)
+	counts, err := h.reactionUsecase.Unreact(actor, postID, c.Params("type"))
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "reaction.removed", counts)
+}
+
+// @Summary Reconcile reaction counters
+// @Description Recount reactions from the database and repair Redis counters that drifted; also runs every REACTION_RECONCILE_INTERVAL
+// @Tags admin
+// @Produce  json
+// @Success 200 {object} entity.ReconcileResult
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Security BearerAuth
+// @Router /api/v1/admin/reactions/reconcile [post]
+func (h *ReactionHandler) Reconcile(c *fiber.Ctx) error {
+	result, err := h.reactionUsecase.Reconcile()
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "reaction.reconciled", result)
+}
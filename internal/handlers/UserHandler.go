@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
 	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/middleware"
 	"User-Post-Backend/internal/repository"
 	"User-Post-Backend/internal/usecase"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -23,66 +26,197 @@ func NewUserHandler(app *fiber.App, db *gorm.DB, cache *infra.RedisClient) {
 
 	apiv1 := app.Group("/api/v1")
 
-	apiv1.Post("/users", handler.Create)
+	apiv1.Post("/users", middleware.Authorize(constant.PermManageUsers), handler.Create)
+	apiv1.Post("/users/register", handler.Register)
+	apiv1.Put("/users/me/password", handler.ChangePassword)
 	apiv1.Get("/users", handler.GetAll)
+	apiv1.Get("/users/autocomplete", handler.Autocomplete)
 	apiv1.Get("/users/:id", handler.GetByID)
 	apiv1.Put("/users/:id", handler.Update)
-	apiv1.Delete("/users/:id", handler.Delete)
+	apiv1.Patch("/users/:id", handler.Patch)
+	apiv1.Delete("/users/:id", middleware.Authorize(constant.PermDeleteUser), handler.Delete)
+	apiv1.Post("/users/:id/restore", middleware.Authorize(constant.PermManageUsers), handler.Restore)
 }
 
 // @Summary Get all users
-// @Description Get a list of all users
+// @Description Get a list of all users. Email, status and role are only shown to the user and to user managers.
 // @Tags users
 // @Accept  json
 // @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Rows to skip when not using a cursor"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param name query string false "Exact name; use name[contains] for a substring match"
+// @Param created_at[gte] query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_at[lte] query string false "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "Comma separated id, name, created_at; prefix with - for descending"
 // @Success 200 {array} entity.User
+// @Failure 400 {object} helpers.StandardResponse "Invalid cursor, filter or sort"
 // @Router /api/v1/users [get]
 func (h *UserHandler) GetAll(c *fiber.Ctx) error {
-	users, err := h.userUsecase.GetAll()
+	page, err := helpers.ParsePageRequest(c)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "successfully", users)
+	query, err := helpers.ParseListQuery(c, entity.UserQuerySpec)
+	if err != nil {
+		return err
+	}
+	users, meta, err := h.userUsecase.GetAll(page, query)
+	if err != nil {
+		return err
+	}
+	return helpers.SendPageResponse(c, fiber.StatusOK, "user.listed", visibleUsers(c, users), meta)
+}
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 25
+)
+
+// @Summary Autocomplete user names
+// @Description Suggest active users whose name contains or resembles the typed text, most similar first, e.g. for mentions
+// @Tags users
+// @Produce  json
+// @Param q query string true "Typed text"
+// @Param limit query int false "Number of suggestions (default 10, max 25)"
+// @Success 200 {array} entity.UserSuggestion
+// @Failure 400 {object} helpers.StandardResponse "Missing text"
+// @Router /api/v1/users/autocomplete [get]
+func (h *UserHandler) Autocomplete(c *fiber.Ctx) error {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		return constant.ErrInvalidQuery.WithDetail("q is required")
+	}
+	limit := c.QueryInt("limit", defaultAutocompleteLimit)
+	if limit <= 0 {
+		limit = defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	suggestions, err := h.userUsecase.Autocomplete(text, limit)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "user.suggested", suggestions)
 }
 
 // @Summary Get a user by ID
-// @Description Get a single user by ID
+// @Description Get a single user by ID. Email, status and role are only shown to the user and to user managers.
 // @Tags users
 // @Accept  json
 // @Produce  json
 // @Param id path int true "User ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} entity.User
+// @Success 304 "Not modified"
+// @Header 200 {string} ETag "Version of the user"
 // @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetByID(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
 	}
 	user, err := h.userUsecase.GetByID(id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
+	}
+	if helpers.NotModified(c, user.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "successfully", user)
+	return helpers.SendResponse(c, fiber.StatusOK, "user.retrieved", visibleUser(c, user))
 }
 
 // @Summary Create a new user
-// @Description Add a new user to the database
+// @Description Add a new user to the database (admin only); others sign up through /api/v1/users/register
 // @Tags users
 // @Accept  json
 // @Produce  json
 // @Param user body entity.CreateUser true "User data"
-// @Success 201 {string} string "User created"
+// @Success 201 {object} entity.User
+// @Header 201 {string} Location "URL of the new user"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 409 {object} helpers.StandardResponse "Email is already registered"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
 // @Router /api/v1/users [post]
 func (h *UserHandler) Create(c *fiber.Ctx) error {
 	var user entity.CreateUser
 	if err := c.BodyParser(&user); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(user); err != nil {
+		return err
 	}
 
-	if err := h.userUsecase.Create(user); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	created, err := h.userUsecase.Create(user)
+	if err != nil {
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusCreated, "successfully", nil)
+	c.Location("/api/v1/users/" + strconv.FormatUint(created.ID, 10))
+	return helpers.SendResponse(c, fiber.StatusCreated, "user.created", created)
+}
+
+// @Summary Register a new account
+// @Description Create an active user account with email and password
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param user body entity.RegisterUser true "Account data"
+// @Success 201 {object} entity.User
+// @Header 201 {string} Location "URL of the new user"
+// @Failure 409 {object} helpers.StandardResponse "Email is already registered"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Router /api/v1/users/register [post]
+func (h *UserHandler) Register(c *fiber.Ctx) error {
+	var user entity.RegisterUser
+	if err := c.BodyParser(&user); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(user); err != nil {
+		return err
+	}
+
+	created, err := h.userUsecase.Register(user)
+	if err != nil {
+		return err
+	}
+	c.Location("/api/v1/users/" + strconv.FormatUint(created.ID, 10))
+	return helpers.SendResponse(c, fiber.StatusCreated, "user.registered", created)
+}
+
+// @Summary Change password
+// @Description Change the password of the authenticated user
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param password body entity.ChangePassword true "Current and new password"
+// @Success 200 {string} string "Password changed"
+// @Failure 401 {object} helpers.StandardResponse "Invalid credentials"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/users/me/password [put]
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	actor, ok := middleware.CurrentActor(c)
+	if !ok {
+		return constant.ErrAuthRequired
+	}
+
+	var req entity.ChangePassword
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+
+	err := h.userUsecase.ChangePassword(actor.UserID, req)
+	if err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "user.password_changed", nil)
 }
 
 // @Summary Update an existing user
@@ -91,24 +225,84 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "User ID"
-// @Param user body entity.CreateUser true "User data"
-// @Success 200 {string} string "User updated"
+// @Param user body entity.UpdateUser true "User data"
+// @Param If-Match header string false "ETag the update is based on"
+// @Success 200 {object} entity.User
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 412 {object} helpers.StandardResponse "The user changed since it was read"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
 // @Router /api/v1/users/{id} [put]
 func (h *UserHandler) Update(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
 	}
 
-	var user entity.User
-	if err := c.BodyParser(&user); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
+	}
+
+	var req entity.UpdateUser
+	if err := c.BodyParser(&req); err != nil {
+		return constant.ErrInvalidBody.Wrap(err)
+	}
+	if err := helpers.Validate(req); err != nil {
+		return err
+	}
+	user := entity.User{ID: id, Name: req.Name, Email: req.Email}
+	actor, _ := middleware.CurrentActor(c)
+	updated, err := h.userUsecase.Update(actor, user, version)
+	if err != nil {
+		return err
+	}
+	helpers.SetETag(c, updated.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "user.updated", updated)
+}
+
+// @Summary Partially update a user
+// @Description Apply a JSON Merge Patch (RFC 7386) or JSON Patch (RFC 6902) to the name and email of a user; removing the email clears it
+// @Tags users
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param patch body entity.UserPatch true "Merge patch, or an array of JSON Patch operations"
+// @Param If-Match header string false "ETag the patch is based on"
+// @Success 200 {object} entity.User
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} helpers.StandardResponse "Invalid ID or patch document"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Failure 409 {object} helpers.StandardResponse "Email is already registered or a test operation failed"
+// @Failure 412 {object} helpers.StandardResponse "The user changed since it was read"
+// @Failure 415 {object} helpers.StandardResponse "Unsupported patch media type"
+// @Failure 422 {object} helpers.StandardResponse "Validation failed"
+// @Security BearerAuth
+// @Router /api/v1/users/{id} [patch]
+func (h *UserHandler) Patch(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
+	}
+
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
+	}
+	patch, err := helpers.ParsePatch(c)
+	if err != nil {
+		return err
 	}
-	user.ID = id
-	if err := h.userUsecase.Update(user); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	actor, _ := middleware.CurrentActor(c)
+	updated, err := h.userUsecase.Patch(actor, id, patch, version)
+	if err != nil {
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "update successfully", nil)
+	helpers.SetETag(c, updated.Version)
+	return helpers.SendResponse(c, fiber.StatusOK, "user.updated", updated)
 }
 
 // @Summary Delete a user
@@ -117,15 +311,45 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "User ID"
+// @Param If-Match header string false "ETag the deletion is based on"
 // @Success 200 {string} string "User deleted"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 412 {object} helpers.StandardResponse "The user changed since it was read"
+// @Security BearerAuth
 // @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) Delete(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+		return constant.ErrInvalidID
+	}
+	version, err := helpers.IfMatch(c)
+	if err != nil {
+		return err
+	}
+	if err := h.userUsecase.Delete(id, version); err != nil {
+		return err
+	}
+	return helpers.SendResponse(c, fiber.StatusOK, "user.deleted", nil)
+}
+
+// @Summary Restore a user
+// @Description Restore a soft-deleted user together with the posts deleted with them
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {string} string "User restored"
+// @Failure 403 {object} helpers.StandardResponse "Forbidden"
+// @Failure 404 {object} helpers.StandardResponse "Data not found"
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/restore [post]
+func (h *UserHandler) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return constant.ErrInvalidID
 	}
-	if err := h.userUsecase.Delete(id); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	if err := h.userUsecase.Restore(id); err != nil {
+		return err
 	}
-	return helpers.SendResponse(c, fiber.StatusOK, "deleted successfully", nil)
+	return helpers.SendResponse(c, fiber.StatusOK, "user.restored", nil)
 }
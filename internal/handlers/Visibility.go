@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// visibleUser returns user as the caller may see it: in full to the user
+// themselves and to user managers, and without private fields to anyone else,
+// anonymous callers included.
+func visibleUser(c *fiber.Ctx, user entity.User) entity.User {
+	actor, ok := middleware.CurrentActor(c)
+	if ok && (actor.UserID == user.ID || actor.Can(constant.PermManageUsers)) {
+		return user
+	}
+	return user.Public()
+}
+
+func visibleUsers(c *fiber.Ctx, users []entity.User) []entity.User {
+	for i := range users {
+		users[i] = visibleUser(c, users[i])
+	}
+	return users
+}
+
+// visibleAuthors applies visibleUser to the embedded author of each post.
+func visibleAuthors(c *fiber.Ctx, posts []entity.Post) []entity.Post {
+	for i := range posts {
+		if posts[i].Author != nil {
+			author := visibleUser(c, *posts[i].Author)
+			posts[i].Author = &author
+		}
+	}
+	return posts
+}
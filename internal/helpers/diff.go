@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"strings"
+
+	"User-Post-Backend/internal/entity"
+)
+
+// maxDiffCells caps the LCS table at about 8 MB. Changed regions too large
+// for it are shown as removed and re-added as a whole instead.
+const maxDiffCells = 1 << 20
+
+// LineDiff returns the line-by-line edit script turning from into to, based
+// on the longest common subsequence of lines. Lines shared at the start and
+// end are matched up front, so the table only covers the changed region.
+func LineDiff(from string, to string) []entity.DiffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]entity.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		diff = append(diff, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+	diff = append(diff, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, entity.DiffLine{Op: entity.DiffEqual, Text: line})
+	}
+	return diff
+}
+
+func lcsDiff(a []string, b []string) []entity.DiffLine {
+	diff := make([]entity.DiffLine, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			diff = append(diff, entity.DiffLine{Op: entity.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, entity.DiffLine{Op: entity.DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, entity.DiffLine{Op: entity.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, entity.DiffLine{Op: entity.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, entity.DiffLine{Op: entity.DiffInsert, Text: b[j]})
+	}
+	return diff
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// render writes a diff in unified style: " " equal, "-" delete, "+" insert.
+func render(diff []entity.DiffLine) []string {
+	marks := map[string]string{entity.DiffEqual: " ", entity.DiffDelete: "-", entity.DiffInsert: "+"}
+	lines := make([]string, 0, len(diff))
+	for _, line := range diff {
+		lines = append(lines, marks[line.Op]+line.Text)
+	}
+	return lines
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []string
+	}{
+		{"identical", "a\nb", "a\nb", []string{" a", " b"}},
+		{"empty to text", "", "a", []string{"-", "+a"}},
+		{"append", "a\nb", "a\nb\nc", []string{" a", " b", "+c"}},
+		{"remove middle", "a\nb\nc", "a\nc", []string{" a", "-b", " c"}},
+		{"replace middle", "a\nb\nc", "a\nx\nc", []string{" a", "-b", "+x", " c"}},
+		{"reorder", "a\nb\nc", "c\na\nb", []string{"+c", " a", " b", "-c"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, render(LineDiff(test.from, test.to)))
+		})
+	}
+}
+
+func TestLineDiffLargeChange(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = prefix + strings.Repeat("x", i%7)
+		}
+		return out
+	}
+	from := append(append([]string{"head"}, lines("a", 2000)...), "tail")
+	to := append(append([]string{"head"}, lines("b", 2000)...), "tail")
+
+	diff := LineDiff(strings.Join(from, "\n"), strings.Join(to, "\n"))
+	assert.Len(t, diff, 4002)
+	assert.Equal(t, entity.DiffLine{Op: entity.DiffEqual, Text: "head"}, diff[0])
+	assert.Equal(t, entity.DiffDelete, diff[1].Op)
+	assert.Equal(t, entity.DiffInsert, diff[2001].Op)
+	assert.Equal(t, entity.DiffLine{Op: entity.DiffEqual, Text: "tail"}, diff[4001])
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+
+	"User-Post-Backend/internal/constant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag formats a row version as a strong entity tag, e.g. "3". Data in the
+// response that changes without a new version, such as reaction counts, is
+// added as a suffix, e.g. "3-1k2f9"; If-Match only looks at the version.
+func ETag(version uint64, suffix ...string) string {
+	return strconv.Quote(strings.Join(append([]string{strconv.FormatUint(version, 10)}, suffix...), "-"))
+}
+
+// SetETag sends the entity tag of version with the response.
+func SetETag(c *fiber.Ctx, version uint64, suffix ...string) {
+	c.Set(fiber.HeaderETag, ETag(version, suffix...))
+}
+
+// NotModified sets the ETag and reports whether If-None-Match already names
+// it, in which case the caller should answer 304 without a body.
+func NotModified(c *fiber.Ctx, version uint64, suffix ...string) bool {
+	current := ETag(version, suffix...)
+	c.Set(fiber.HeaderETag, current)
+	header := c.Get(fiber.HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch returns the version the client expects from If-Match, or 0 when
+// the header is absent or "*" and any version is accepted.
+func IfMatch(c *fiber.Ctx) (uint64, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	value, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, constant.ErrVersionMismatch
+	}
+	value, _, _ = strings.Cut(value, "-")
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || version == 0 {
+		return 0, constant.ErrVersionMismatch
+	}
+	return version, nil
+}
@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"3"`, ETag(3))
+	assert.Equal(t, `"3-abc"`, ETag(3, "abc"))
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		version uint64
+		err     error
+	}{
+		{"", 0, nil},
+		{"*", 0, nil},
+		{`"4"`, 4, nil},
+		{` "4-1k2f9" `, 4, nil},
+		{"4", 0, constant.ErrVersionMismatch},
+		{`"0"`, 0, constant.ErrVersionMismatch},
+		{`"v4"`, 0, constant.ErrVersionMismatch},
+		{`W/"4"`, 0, constant.ErrVersionMismatch},
+	}
+	for _, test := range tests {
+		c := newTestCtx(t, "", map[string]string{fiber.HeaderIfMatch: test.header})
+		version, err := IfMatch(c)
+		if test.err != nil {
+			assert.True(t, errors.Is(err, test.err), "If-Match %q: %v", test.header, err)
+			continue
+		}
+		require.NoError(t, err, test.header)
+		assert.Equal(t, test.version, version, test.header)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"3-x"`, true},
+		{`W/"3-x"`, true},
+		{`"2-x", "3-x"`, true},
+		{"*", true},
+		{`"3"`, false},
+		{`"3-y"`, false},
+	}
+	for _, test := range tests {
+		c := newTestCtx(t, "", map[string]string{fiber.HeaderIfNoneMatch: test.header})
+		assert.Equal(t, test.want, NotModified(c, 3, "x"), "If-None-Match %q", test.header)
+		assert.Equal(t, `"3-x"`, string(c.Response().Header.Peek(fiber.HeaderETag)))
+	}
+}
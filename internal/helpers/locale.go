@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"User-Post-Backend/internal/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language returns the catalog language negotiated from the request's
+// Accept-Language header.
+func Language(c *fiber.Ctx) string {
+	return i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// Localize returns catalog message id in the request's language.
+func Localize(c *fiber.Ctx, id string, args ...interface{}) string {
+	return i18n.Translate(Language(c), id, args...)
+}
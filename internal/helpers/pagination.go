@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ParsePageRequest reads limit, offset and cursor from the query string. A
+// cursor takes precedence over offset.
+func ParsePageRequest(c *fiber.Ctx) (entity.PageRequest, error) {
+	page := entity.PageRequest{
+		Limit:  c.QueryInt("limit", DefaultPageLimit),
+		Offset: c.QueryInt("offset", 0),
+		Cursor: c.Query("cursor"),
+	}
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	if page.Cursor != "" {
+		id, backward, err := DecodeCursor(page.Cursor)
+		if err != nil {
+			return page, err
+		}
+		page.CursorID = id
+		page.Backward = backward
+		page.Offset = 0
+	}
+	return page, nil
+}
+
+// EncodeCursor returns an opaque cursor pointing after (or, when backward,
+// before) the row with the given id.
+func EncodeCursor(id uint64, backward bool) string {
+	direction := "next"
+	if backward {
+		direction = "prev"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(direction + ":" + strconv.FormatUint(id, 10)))
+}
+
+func DecodeCursor(cursor string) (uint64, bool, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false, constant.ErrInvalidCursor
+	}
+	direction, value, found := strings.Cut(string(raw), ":")
+	if !found || (direction != "next" && direction != "prev") {
+		return 0, false, constant.ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false, constant.ErrInvalidCursor
+	}
+	return id, direction == "prev", nil
+}
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// newTestCtx returns a request context for query and headers, without a
+// running server.
+func newTestCtx(t *testing.T, query string, headers map[string]string) *fiber.Ctx {
+	t.Helper()
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	t.Cleanup(func() { app.ReleaseCtx(c) })
+	c.Request().SetRequestURI("/?" + query)
+	for name, value := range headers {
+		c.Request().Header.Set(name, value)
+	}
+	return c
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		id       uint64
+		backward bool
+	}{
+		{1, false},
+		{1, true},
+		{18446744073709551615, false},
+	} {
+		id, backward, err := DecodeCursor(EncodeCursor(test.id, test.backward))
+		require.NoError(t, err)
+		assert.Equal(t, test.id, id)
+		assert.Equal(t, test.backward, backward)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for _, cursor := range []string{
+		"!!!",
+		encode("next"),
+		encode("up:1"),
+		encode("next:"),
+		encode("next:-1"),
+		encode("prev:abc"),
+	} {
+		_, _, err := DecodeCursor(cursor)
+		assert.True(t, errors.Is(err, constant.ErrInvalidCursor), "cursor %q: %v", cursor, err)
+	}
+}
+
+func TestParsePageRequest(t *testing.T) {
+	page, err := ParsePageRequest(newTestCtx(t, "", nil))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageLimit, page.Limit)
+
+	page, err = ParsePageRequest(newTestCtx(t, "limit=1000&offset=-5", nil))
+	require.NoError(t, err)
+	assert.Equal(t, MaxPageLimit, page.Limit)
+	assert.Equal(t, 0, page.Offset)
+
+	page, err = ParsePageRequest(newTestCtx(t, "offset=40&cursor="+EncodeCursor(9, true), nil))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), page.CursorID)
+	assert.True(t, page.Backward)
+	assert.Equal(t, 0, page.Offset, "a cursor replaces the offset")
+
+	_, err = ParsePageRequest(newTestCtx(t, "cursor=bogus", nil))
+	assert.True(t, errors.Is(err, constant.ErrInvalidCursor))
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	MIMEMergePatchJSON = "application/merge-patch+json"
+	MIMEJSONPatchJSON  = "application/json-patch+json"
+)
+
+// ParsePatch reads a PATCH body. Plain application/json is treated as a
+// merge patch so existing clients keep working.
+func ParsePatch(c *fiber.Ctx) (entity.Patch, error) {
+	contentType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	switch contentType {
+	case MIMEMergePatchJSON, MIMEJSONPatchJSON:
+	case fiber.MIMEApplicationJSON:
+		contentType = MIMEMergePatchJSON
+	default:
+		return entity.Patch{}, constant.ErrUnsupportedPatch
+	}
+	return entity.Patch{ContentType: contentType, Document: c.Body()}, nil
+}
+
+// ApplyPatch applies patch to the JSON form of current and decodes the result
+// into target, rejecting fields target does not have.
+func ApplyPatch(patch entity.Patch, current interface{}, target interface{}) error {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch patch.ContentType {
+	case MIMEMergePatchJSON:
+		patched, err = jsonpatch.MergePatch(original, patch.Document)
+	case MIMEJSONPatchJSON:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch.Document)
+		if err == nil {
+			patched, err = operations.Apply(original)
+		}
+	default:
+		return constant.ErrUnsupportedPatch
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return constant.ErrPatchTestFailed.Wrap(err)
+	}
+	if err != nil {
+		return constant.ErrInvalidPatch.WithDetail("%v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return constant.ErrInvalidPatch.WithDetail("%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	current := entity.PostPatch{Title: "old", Content: "body"}
+	tests := []struct {
+		name        string
+		contentType string
+		document    string
+		want        entity.PostPatch
+		err         error
+	}{
+		{
+			name:        "merge patch",
+			contentType: MIMEMergePatchJSON,
+			document:    `{"title":"new"}`,
+			want:        entity.PostPatch{Title: "new", Content: "body"},
+		},
+		{
+			name:        "json patch",
+			contentType: MIMEJSONPatchJSON,
+			document:    `[{"op":"test","path":"/title","value":"old"},{"op":"replace","path":"/content","value":"text"}]`,
+			want:        entity.PostPatch{Title: "old", Content: "text"},
+		},
+		{
+			name:        "failed test",
+			contentType: MIMEJSONPatchJSON,
+			document:    `[{"op":"test","path":"/title","value":"other"}]`,
+			err:         constant.ErrPatchTestFailed,
+		},
+		{
+			name:        "unknown field",
+			contentType: MIMEMergePatchJSON,
+			document:    `{"user_id":2}`,
+			err:         constant.ErrInvalidPatch,
+		},
+		{
+			name:        "malformed document",
+			contentType: MIMEJSONPatchJSON,
+			document:    `{"op":"replace"}`,
+			err:         constant.ErrInvalidPatch,
+		},
+		{
+			name:        "unsupported type",
+			contentType: "text/plain",
+			document:    `title=new`,
+			err:         constant.ErrUnsupportedPatch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got entity.PostPatch
+			err := ApplyPatch(entity.Patch{ContentType: test.contentType, Document: []byte(test.document)}, current, &got)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParsePatch(t *testing.T) {
+	for contentType, want := range map[string]string{
+		MIMEJSONPatchJSON:                 MIMEJSONPatchJSON,
+		MIMEMergePatchJSON:                MIMEMergePatchJSON,
+		"application/json; charset=utf-8": MIMEMergePatchJSON,
+	} {
+		patch, err := ParsePatch(newTestCtx(t, "", map[string]string{fiber.HeaderContentType: contentType}))
+		require.NoError(t, err, contentType)
+		assert.Equal(t, want, patch.ContentType, contentType)
+	}
+
+	_, err := ParsePatch(newTestCtx(t, "", map[string]string{fiber.HeaderContentType: "text/plain"}))
+	assert.True(t, errors.Is(err, constant.ErrUnsupportedPatch))
+}
@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// reservedQueryKeys are consumed elsewhere and never treated as filters.
+var reservedQueryKeys = map[string]bool{
+	"limit":   true,
+	"offset":  true,
+	"cursor":  true,
+	"sort":    true,
+	"include": true,
+}
+
+// ParseListQuery turns "field=value", "field[op]=value" and
+// "sort=-field,field" query parameters into a ListQuery, rejecting any field
+// or operator that spec does not whitelist.
+func ParseListQuery(c *fiber.Ctx, spec entity.QuerySpec) (entity.ListQuery, error) {
+	var query entity.ListQuery
+	var parseErr error
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if parseErr != nil || reservedQueryKeys[string(key)] {
+			return
+		}
+		filter, err := parseFilter(string(key), string(value), spec)
+		if err != nil {
+			parseErr = err
+			return
+		}
+		query.Filters = append(query.Filters, filter)
+	})
+	if parseErr != nil {
+		return query, parseErr
+	}
+
+	if raw := c.Query("sort"); raw != "" {
+		if c.Query("cursor") != "" {
+			return query, constant.ErrInvalidQuery.WithDetail("cursor pagination cannot be combined with sort")
+		}
+		for _, name := range strings.Split(raw, ",") {
+			desc := strings.HasPrefix(name, "-")
+			name = strings.TrimPrefix(name, "-")
+			field, ok := spec[name]
+			if !ok || !field.Sortable {
+				return query, constant.ErrInvalidQuery.WithDetail("cannot sort by %q", name)
+			}
+			query.Sort = append(query.Sort, entity.SortField{Field: name, Column: field.Column, Desc: desc})
+		}
+	}
+	return query, nil
+}
+
+// ParseInclude reads the comma separated include parameter and rejects any
+// relation not listed in allowed.
+func ParseInclude(c *fiber.Ctx, allowed ...string) (map[string]bool, error) {
+	include := map[string]bool{}
+	if raw := c.Query("include"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			if !slices.Contains(allowed, name) {
+				return nil, constant.ErrInvalidQuery.WithDetail("cannot include %q", name)
+			}
+			include[name] = true
+		}
+	}
+	return include, nil
+}
+
+func parseFilter(key string, raw string, spec entity.QuerySpec) (entity.Filter, error) {
+	name, op := key, entity.FilterEq
+	if open := strings.IndexByte(key, '['); open > 0 && strings.HasSuffix(key, "]") {
+		name, op = key[:open], key[open+1:len(key)-1]
+	}
+
+	field, ok := spec[name]
+	if !ok {
+		return entity.Filter{}, constant.ErrInvalidQuery.WithDetail("unknown filter field %q", name)
+	}
+	if !slices.Contains(field.Ops, op) {
+		return entity.Filter{}, constant.ErrInvalidQuery.WithDetail("unsupported operator %q for field %q", op, name)
+	}
+
+	filter := entity.Filter{Field: name, Column: field.Column, Op: op, Raw: raw}
+	switch field.Type {
+	case entity.FieldNumber:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return filter, constant.ErrInvalidQuery.WithDetail("field %q expects a number", name)
+		}
+		filter.Value = value
+	case entity.FieldTime:
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			value, err = time.Parse(time.DateOnly, raw)
+		}
+		if err != nil {
+			return filter, constant.ErrInvalidQuery.WithDetail("field %q expects an RFC 3339 timestamp or a date", name)
+		}
+		filter.Value = value
+	default:
+		filter.Value = raw
+	}
+	return filter, nil
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		filters []entity.Filter
+		sort    []entity.SortField
+		err     error
+	}{
+		{
+			name:    "eq by default",
+			query:   "title=hello",
+			filters: []entity.Filter{{Field: "title", Column: "title", Op: entity.FilterEq, Raw: "hello", Value: "hello"}},
+		},
+		{
+			name:    "explicit operator",
+			query:   "title[contains]=hel",
+			filters: []entity.Filter{{Field: "title", Column: "title", Op: entity.FilterContains, Raw: "hel", Value: "hel"}},
+		},
+		{
+			name:    "number",
+			query:   "user_id=7",
+			filters: []entity.Filter{{Field: "user_id", Column: "user_id", Op: entity.FilterEq, Raw: "7", Value: uint64(7)}},
+		},
+		{
+			name:  "date",
+			query: "created_at[gte]=2024-01-02",
+			filters: []entity.Filter{{
+				Field: "created_at", Column: "created_at", Op: entity.FilterGte, Raw: "2024-01-02",
+				Value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name:  "reserved keys are not filters",
+			query: "limit=5&offset=10&include=author",
+		},
+		{
+			name:  "sort",
+			query: "sort=-created_at,title",
+			sort: []entity.SortField{
+				{Field: "created_at", Column: "created_at", Desc: true},
+				{Field: "title", Column: "title"},
+			},
+		},
+		{name: "unknown field", query: "password=x", err: constant.ErrInvalidQuery},
+		{name: "unsupported operator", query: "user_id[contains]=1", err: constant.ErrInvalidQuery},
+		{name: "bad number", query: "user_id=abc", err: constant.ErrInvalidQuery},
+		{name: "bad time", query: "created_at[gt]=yesterday", err: constant.ErrInvalidQuery},
+		{name: "unsortable field", query: "sort=user_id", err: constant.ErrInvalidQuery},
+		{name: "sort with cursor", query: "sort=title&cursor=abc", err: constant.ErrInvalidQuery},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := ParseListQuery(newTestCtx(t, test.query, nil), entity.PostQuerySpec)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.filters, query.Filters)
+			assert.Equal(t, test.sort, query.Sort)
+		})
+	}
+}
+
+func TestParseListQueryKeysDoNotCollide(t *testing.T) {
+	queries := []string{
+		"title=a&user_id=1",
+		"title=a%26user_id%5Beq%5D%3D1",
+		"title=a%26user_id%3D1",
+	}
+	seen := map[string]string{}
+	for _, raw := range queries {
+		query, err := ParseListQuery(newTestCtx(t, raw, nil), entity.PostQuerySpec)
+		require.NoError(t, err, raw)
+		key := query.Key()
+		if other, ok := seen[key]; ok {
+			t.Fatalf("%q and %q share the cache key %q", other, raw, key)
+		}
+		seen[key] = raw
+	}
+}
+
+func TestParseInclude(t *testing.T) {
+	include, err := ParseInclude(newTestCtx(t, "include=author", nil), "author")
+	require.NoError(t, err)
+	assert.True(t, include["author"])
+
+	_, err = ParseInclude(newTestCtx(t, "include=password", nil), "author")
+	assert.True(t, errors.Is(err, constant.ErrInvalidQuery))
+}
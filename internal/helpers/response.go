@@ -1,28 +1,109 @@
 package helpers
 
 import (
+	"os"
+	"strings"
+
+	"User-Post-Backend/internal/entity"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
+const MIMEProblemJSON = "application/problem+json"
+
 type StandardResponse struct {
 	Status  int         `json:"status"`
+	Code    string      `json:"code,omitempty"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
+	Errors  interface{} `json:"errors,omitempty"`
 }
 
-func SendResponse(c *fiber.Ctx, status int, message string, data interface{}) error {
+// SendResponse answers with the catalog message messageID in the request's
+// language.
+func SendResponse(c *fiber.Ctx, status int, messageID string, data interface{}) error {
 	response := StandardResponse{
 		Status:  status,
-		Message: message,
+		Message: Localize(c, messageID),
 		Data:    data,
 	}
+	c.Set(fiber.HeaderContentLanguage, Language(c))
 	return c.Status(status).JSON(response)
 }
 
-func SendErrorResponse(c *fiber.Ctx, status int, message string) error {
+func SendPageResponse(c *fiber.Ctx, status int, messageID string, data interface{}, meta entity.PageMeta) error {
 	response := StandardResponse{
 		Status:  status,
-		Message: message,
+		Message: Localize(c, messageID),
+		Data:    data,
+		Meta:    meta,
 	}
+	c.Set(fiber.HeaderContentLanguage, Language(c))
 	return c.Status(status).JSON(response)
 }
+
+// ProblemDetails is the RFC 7807 error body. Extensions carries the error
+// code and, for validation errors, the failed fields.
+type ProblemDetails struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func SendErrorResponse(c *fiber.Ctx, status int, messageID string) error {
+	return RenderError(c, status, "", Localize(c, messageID), nil)
+}
+
+// RenderError writes every error response of the API, as problem details
+// when the client accepts application/problem+json or ERROR_FORMAT=problem,
+// and as a StandardResponse otherwise. message is expected to be localized.
+func RenderError(c *fiber.Ctx, status int, code string, message string, fields []FieldError) error {
+	c.Set(fiber.HeaderContentLanguage, Language(c))
+	if !wantsProblem(c) {
+		response := StandardResponse{
+			Status:  status,
+			Code:    code,
+			Message: message,
+		}
+		if len(fields) > 0 {
+			response.Errors = fields
+		}
+		return c.Status(status).JSON(response)
+	}
+
+	problem := ProblemDetails{
+		Type:     problemType(code),
+		Title:    utils.StatusMessage(status),
+		Status:   status,
+		Detail:   message,
+		Instance: c.OriginalURL(),
+	}
+	if code != "" {
+		problem.Extensions = map[string]interface{}{"code": code}
+		if len(fields) > 0 {
+			problem.Extensions["errors"] = fields
+		}
+	}
+	return c.Status(status).JSON(problem, MIMEProblemJSON)
+}
+
+func wantsProblem(c *fiber.Ctx) bool {
+	if strings.Contains(c.Get(fiber.HeaderAccept), MIMEProblemJSON) {
+		return true
+	}
+	return strings.EqualFold(os.Getenv("ERROR_FORMAT"), "problem")
+}
+
+// problemType turns an error code such as NOT_FOUND into a relative type URI
+// like /problems/not-found; errors without a code use about:blank.
+func problemType(code string) string {
+	if code == "" {
+		return "about:blank"
+	}
+	return "/problems/" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
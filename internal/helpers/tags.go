@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const maxTagLength = 50
+
+var (
+	tagPattern = regexp.MustCompile(`^#?[\p{L}\p{N}_]{1,50}$`)
+	// A hashtag starts the content or follows a character that cannot be part
+	// of a word, so "a#b" and URL fragments are not hashtags.
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/#])#([\p{L}\p{N}_]+)`)
+)
+
+// IsTag reports whether s is a valid tag name, with or without a leading #.
+func IsTag(s string) bool {
+	return tagPattern.MatchString(strings.TrimSpace(s))
+}
+
+// NormalizeTag lower-cases a tag and drops its leading #, so "#Go" and "go"
+// name the same tag.
+func NormalizeTag(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+}
+
+// NormalizeTags normalizes tags and drops duplicates, keeping the first.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// Hashtags returns the normalized #hashtags of content in order of first
+// use. Hashtags without a letter, such as "#1", and ones longer than a tag
+// may be are skipped.
+func Hashtags(content string) []string {
+	var tags []string
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		tag := match[1]
+		if len([]rune(tag)) > maxTagLength || strings.IndexFunc(tag, unicode.IsLetter) < 0 {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return NormalizeTags(tags)
+}
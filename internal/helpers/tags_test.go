@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTag(t *testing.T) {
+	for tag, want := range map[string]bool{
+		"go":                    true,
+		"#Go":                   true,
+		"rust_lang":             true,
+		"café":                  true,
+		"":                      false,
+		"#":                     false,
+		"two words":             false,
+		"c++":                   false,
+		strings.Repeat("a", 50): true,
+		strings.Repeat("a", 51): false,
+	} {
+		assert.Equal(t, want, IsTag(tag), "IsTag(%q)", tag)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"go", "rust"}, NormalizeTags([]string{"#Go", " go ", "Rust", "", "#"}))
+}
+
+func TestHashtags(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"no tags here", nil},
+		{"#Go is fun #go", []string{"go"}},
+		{"start #one, then (#two) and #three.", []string{"one", "two", "three"}},
+		{"#Café au lait", []string{"café"}},
+		{"#1 and #2024 are numbers", []string{}},
+		{"mail a#b or http://x/#frag", []string{}},
+		{"entity &#39; is not a tag", []string{}},
+		{"#" + strings.Repeat("a", 51) + " #ok", []string{"ok"}},
+	}
+	for _, test := range tests {
+		got := Hashtags(test.content)
+		if len(test.want) == 0 {
+			assert.Empty(t, got, test.content)
+			continue
+		}
+		assert.Equal(t, test.want, got, test.content)
+	}
+}
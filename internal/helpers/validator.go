@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/i18n"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+
+	messageID string
+	arg       string
+}
+
+// ValidationError carries every failed rule of a request body, keyed by the
+// JSON path of the field, e.g. "posts[0].title".
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Localize returns the fields with their messages in language.
+func (e *ValidationError) Localize(language string) []FieldError {
+	fields := make([]FieldError, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field
+		fields[i].Message = field.translate(language)
+	}
+	return fields
+}
+
+func (f FieldError) translate(language string) string {
+	if f.arg != "" {
+		return i18n.Translate(language, f.messageID, f.arg)
+	}
+	return i18n.Translate(language, f.messageID)
+}
+
+// InField returns a copy of e with every field nested under path, so
+// "title" of the third batch operation becomes "operations[2].title".
+func (e *ValidationError) InField(path string) *ValidationError {
+	nested := &ValidationError{Fields: make([]FieldError, len(e.Fields))}
+	for i, field := range e.Fields {
+		nested.Fields[i] = field
+		nested.Fields[i].Field = path + "." + field.Field
+	}
+	return nested
+}
+
+func (e *ValidationError) Unwrap() error {
+	return constant.ErrValidation
+}
+
+// Validate runs the `validate` struct tags of a parsed request body.
+func Validate(body interface{}) error {
+	err := validate.Struct(body)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	validationError := &ValidationError{}
+	for _, fieldError := range fieldErrors {
+		messageID, arg := ruleMessage(fieldError)
+		field := FieldError{
+			Field:     fieldPath(fieldError.Namespace()),
+			Rule:      fieldError.Tag(),
+			Param:     fieldError.Param(),
+			messageID: messageID,
+			arg:       arg,
+		}
+		field.Message = field.translate(i18n.FallbackLanguage)
+		validationError.Fields = append(validationError.Fields, field)
+	}
+	return validationError
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("tag", func(field validator.FieldLevel) bool {
+		return IsTag(field.Field().String())
+	})
+	// max_bytes limits the encoded length, e.g. the 72 bytes bcrypt hashes of
+	// a password, where max would count characters.
+	v.RegisterValidation("max_bytes", func(field validator.FieldLevel) bool {
+		limit, err := strconv.Atoi(field.Param())
+		return err == nil && len(field.Field().String()) <= limit
+	})
+	return v
+}
+
+// fieldPath drops the struct name validator puts in front of the namespace.
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
+}
+
+// ruleMessage returns the catalog message ID of a failed rule and the
+// argument it is formatted with.
+func ruleMessage(fieldError validator.FieldError) (string, string) {
+	switch fieldError.Tag() {
+	case "required", "email":
+		return "validation." + fieldError.Tag(), ""
+	case "required_if", "required_unless":
+		return "validation.required", ""
+	case "tag":
+		return "validation.tag", ""
+	case "max_bytes":
+		return "validation.max_bytes", fieldError.Param()
+	case "oneof":
+		return "validation.oneof", strings.ReplaceAll(fieldError.Param(), " ", ", ")
+	case "min", "max":
+		if fieldError.Kind() == reflect.Slice {
+			return "validation." + fieldError.Tag() + "_items", fieldError.Param()
+		}
+		return "validation." + fieldError.Tag(), fieldError.Param()
+	default:
+		return "validation.rule", fieldError.Tag()
+	}
+}
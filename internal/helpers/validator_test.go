@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMaxBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"72 ASCII bytes", strings.Repeat("a", 72), true},
+		{"73 ASCII bytes", strings.Repeat("a", 73), false},
+		{"36 two-byte runes", strings.Repeat("é", 36), true},
+		{"37 two-byte runes", strings.Repeat("é", 37), false},
+		{"18 four-byte runes", strings.Repeat("😀", 18), true},
+		{"19 four-byte runes", strings.Repeat("😀", 19), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(entity.RegisterUser{Name: "Ann", Email: "ann@example.com", Password: test.password})
+			if test.valid {
+				assert.NoError(t, err)
+				return
+			}
+			var validationError *ValidationError
+			require.True(t, errors.As(err, &validationError), "got %v", err)
+			assert.Equal(t, []FieldError{{
+				Field:     "password",
+				Rule:      "max_bytes",
+				Param:     "72",
+				Message:   "must be at most 72 bytes",
+				messageID: "validation.max_bytes",
+				arg:       "72",
+			}}, validationError.Fields)
+		})
+	}
+}
+
+func TestValidateFieldNames(t *testing.T) {
+	long := strings.Repeat("a", 256)
+	err := Validate(entity.MultiCreatePost{Posts: []entity.Posts{
+		{Title: "ok", Content: "ok"},
+		{Title: long},
+	}})
+
+	var validationError *ValidationError
+	require.True(t, errors.As(err, &validationError), "got %v", err)
+	assert.True(t, errors.Is(err, constant.ErrValidation))
+
+	fields := map[string]string{}
+	for _, field := range validationError.Fields {
+		fields[field.Field] = field.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"posts[1].title":   "max",
+		"posts[1].content": "required",
+	}, fields)
+
+	nested := validationError.InField("operations[2]")
+	assert.Equal(t, "operations[2].posts[1].title", nested.Fields[0].Field)
+}
+
+func TestValidationErrorLocalize(t *testing.T) {
+	err := Validate(entity.ChangeRole{Role: "owner"})
+	var validationError *ValidationError
+	require.True(t, errors.As(err, &validationError), "got %v", err)
+
+	assert.Equal(t, "must be one of: admin, moderator, member", validationError.Fields[0].Message)
+	assert.Equal(t, "harus salah satu dari: admin, moderator, member", validationError.Localize("id")[0].Message)
+}
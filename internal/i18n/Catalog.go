@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FallbackLanguage is used for messages missing from the requested and the
+// default catalog; every message ID must exist in it.
+const FallbackLanguage = "en"
+
+// Every locales/<language>.json file is a catalog, so adding a language only
+// means adding its file.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		raw, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return loaded
+}
+
+// DefaultLanguage is DEFAULT_LANGUAGE when a catalog exists for it, and
+// Indonesian otherwise.
+func DefaultLanguage() string {
+	language := strings.ToLower(os.Getenv("DEFAULT_LANGUAGE"))
+	if _, ok := catalogs[language]; ok {
+		return language
+	}
+	return "id"
+}
+
+// Negotiate picks the best available language for an Accept-Language header
+// such as "en-US,en;q=0.9,id;q=0.8", matching regional tags by their base
+// language.
+func Negotiate(header string) string {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag != "" && quality > 0 {
+			candidates = append(candidates, candidate{tag: strings.ToLower(tag), quality: quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, candidate := range candidates {
+		if candidate.tag == "*" {
+			break
+		}
+		if _, ok := catalogs[candidate.tag]; ok {
+			return candidate.tag
+		}
+		base, _, _ := strings.Cut(candidate.tag, "-")
+		if _, ok := catalogs[base]; ok {
+			return base
+		}
+	}
+	return DefaultLanguage()
+}
+
+// Languages returns the languages that have a catalog.
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Has reports whether the catalog of language itself defines message id.
+func Has(language string, id string) bool {
+	_, ok := catalogs[language][id]
+	return ok
+}
+
+// Lookup finds message id in language, then in the default and the fallback
+// catalog.
+func Lookup(language string, id string) (string, bool) {
+	for _, candidate := range []string{language, DefaultLanguage(), FallbackLanguage} {
+		if message, ok := catalogs[candidate][id]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// Translate returns message id in language formatted with args, or the id
+// itself when no catalog knows it.
+func Translate(language string, id string, args ...interface{}) string {
+	message, ok := Lookup(language, id)
+	if !ok {
+		return id
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Setenv("DEFAULT_LANGUAGE", "")
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "id"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"EN-gb", "en"},
+		{"fr-FR,fr;q=0.9,en;q=0.8,id;q=0.7", "en"},
+		{"en;q=0.5,id;q=0.8", "id"},
+		{"en;q=0,id;q=0.1", "id"},
+		{"en;q=abc", "id"},
+		{"fr,*;q=0.5,en;q=0.1", "id"},
+		{"de", "id"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, Negotiate(test.header), "Accept-Language %q", test.header)
+	}
+}
+
+func TestNegotiateUsesDefaultLanguage(t *testing.T) {
+	t.Setenv("DEFAULT_LANGUAGE", "EN")
+	assert.Equal(t, "en", Negotiate("de"))
+
+	t.Setenv("DEFAULT_LANGUAGE", "fr")
+	assert.Equal(t, "id", Negotiate("de"))
+}
+
+func TestCatalogsDefineTheSameMessages(t *testing.T) {
+	for _, language := range Languages() {
+		for id := range catalogs[FallbackLanguage] {
+			assert.True(t, Has(language, id), "%s catalog has no %s", language, id)
+		}
+		for id := range catalogs[language] {
+			assert.True(t, Has(FallbackLanguage, id), "%s is only in the %s catalog", id, language)
+		}
+	}
+}
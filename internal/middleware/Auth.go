@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const actorKey = "actor"
+
+// PublicRoutes are the write routes a caller needs before it has a token.
+var PublicRoutes = []string{
+	"POST /api/v1/auth/login",
+	"POST /api/v1/auth/refresh",
+	"POST /api/v1/users/register",
+}
+
+// AccountLoader returns the stored user a token was issued to.
+type AccountLoader func(userID uint64) (entity.User, error)
+
+// Authenticate verifies the bearer token when one is sent and stores the
+// caller on the context. Write requests without a token are rejected unless
+// they are listed in public as "METHOD /path".
+//
+// The caller's role and status are loaded on every request instead of being
+// taken from the token, so a suspension or role change applies at once
+// rather than when the access token expires.
+func Authenticate(tokens *infra.JWTManager, loadAccount AccountLoader, public ...string) fiber.Handler {
+	publicRoutes := make(map[string]bool, len(public))
+	for _, route := range public {
+		publicRoutes[route] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		if header != "" {
+			tokenString, found := strings.CutPrefix(header, "Bearer ")
+			if !found {
+				return constant.ErrInvalidToken
+			}
+			claims, err := tokens.Parse(tokenString, infra.AccessToken)
+			if err != nil {
+				return constant.ErrInvalidToken
+			}
+			user, err := loadAccount(claims.UserID)
+			if errors.Is(err, constant.ErrNotFound) {
+				return constant.ErrInvalidToken
+			}
+			if err != nil {
+				return err
+			}
+			if user.Status != constant.UserStatusActive {
+				return constant.ErrAccountInactive
+			}
+			c.Locals(actorKey, entity.Actor{UserID: user.ID, Role: user.Role})
+			return c.Next()
+		}
+
+		if isReadOnly(c.Method()) || publicRoutes[c.Method()+" "+strings.TrimSuffix(c.Path(), "/")] {
+			return c.Next()
+		}
+		return constant.ErrAuthRequired
+	}
+}
+
+// RequireAuth rejects the request when Authenticate did not attach a caller,
+// for read routes that must not be anonymous.
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := CurrentActor(c); !ok {
+			return constant.ErrAuthRequired
+		}
+		return c.Next()
+	}
+}
+
+func CurrentActor(c *fiber.Ctx) (entity.Actor, bool) {
+	actor, ok := c.Locals(actorKey).(entity.Actor)
+	return actor, ok
+}
+
+func isReadOnly(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
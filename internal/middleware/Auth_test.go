@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	secret := []byte("test-secret")
+	tokens := infra.NewHMACJWTManager(secret, "test", time.Minute, time.Hour)
+	expired := infra.NewHMACJWTManager(secret, "test", -time.Minute, -time.Minute)
+	foreign := infra.NewHMACJWTManager([]byte("other-secret"), "test", time.Minute, time.Hour)
+
+	// The token of user 1 still says member; the stored account says admin.
+	users := map[uint64]entity.User{
+		1: {ID: 1, Role: constant.RoleAdmin, Status: constant.UserStatusActive},
+		2: {ID: 2, Role: constant.RoleMember, Status: constant.UserStatusSuspended},
+	}
+	token := func(manager *infra.JWTManager, userID uint64, tokenType infra.TokenType) string {
+		signed, err := manager.Generate(userID, constant.RoleMember, tokenType)
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: HandleError})
+	app.Use("/api/v1", Authenticate(tokens, func(userID uint64) (entity.User, error) {
+		user, ok := users[userID]
+		if !ok {
+			return entity.User{}, constant.ErrRecordNotFound
+		}
+		return user, nil
+	}, PublicRoutes...))
+	app.All("/api/v1/*", func(c *fiber.Ctx) error {
+		actor, ok := CurrentActor(c)
+		if !ok {
+			return c.SendString("anonymous")
+		}
+		return c.SendString(actor.Role)
+	})
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		status        int
+		body          string
+	}{
+		{"anonymous GET", fiber.MethodGet, "/api/v1/posts", "", fiber.StatusOK, "anonymous"},
+		{"anonymous HEAD", fiber.MethodHead, "/api/v1/posts", "", fiber.StatusOK, ""},
+		{"anonymous OPTIONS", fiber.MethodOptions, "/api/v1/posts", "", fiber.StatusOK, "anonymous"},
+		{"login is public", fiber.MethodPost, "/api/v1/auth/login", "", fiber.StatusOK, "anonymous"},
+		{"refresh is public", fiber.MethodPost, "/api/v1/auth/refresh", "", fiber.StatusOK, "anonymous"},
+		{"register is public", fiber.MethodPost, "/api/v1/users/register/", "", fiber.StatusOK, "anonymous"},
+		{"anonymous create post", fiber.MethodPost, "/api/v1/posts", "", fiber.StatusUnauthorized, ""},
+		{"anonymous create user", fiber.MethodPost, "/api/v1/users", "", fiber.StatusUnauthorized, ""},
+		{"anonymous PUT", fiber.MethodPut, "/api/v1/auth/login", "", fiber.StatusUnauthorized, ""},
+		{"anonymous PATCH", fiber.MethodPatch, "/api/v1/posts/1", "", fiber.StatusUnauthorized, ""},
+		{"anonymous DELETE", fiber.MethodDelete, "/api/v1/posts/1", "", fiber.StatusUnauthorized, ""},
+		{"valid token", fiber.MethodPost, "/api/v1/posts", token(tokens, 1, infra.AccessToken), fiber.StatusOK, constant.RoleAdmin},
+		{"not a bearer token", fiber.MethodGet, "/api/v1/posts", "Token abc", fiber.StatusUnauthorized, ""},
+		{"malformed token", fiber.MethodGet, "/api/v1/posts", "Bearer not.a.jwt", fiber.StatusUnauthorized, ""},
+		{"refresh token", fiber.MethodGet, "/api/v1/posts", token(tokens, 1, infra.RefreshToken), fiber.StatusUnauthorized, ""},
+		{"expired token", fiber.MethodGet, "/api/v1/posts", token(expired, 1, infra.AccessToken), fiber.StatusUnauthorized, ""},
+		{"foreign key", fiber.MethodGet, "/api/v1/posts", token(foreign, 1, infra.AccessToken), fiber.StatusUnauthorized, ""},
+		{"suspended user", fiber.MethodGet, "/api/v1/posts", token(tokens, 2, infra.AccessToken), fiber.StatusForbidden, ""},
+		{"deleted user", fiber.MethodGet, "/api/v1/posts", token(tokens, 3, infra.AccessToken), fiber.StatusUnauthorized, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			if test.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, test.authorization)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.status, resp.StatusCode)
+			if test.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.body, string(body))
+			}
+		})
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: HandleError})
+	app.Get("/anonymous", RequireAuth(), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/member", func(c *fiber.Ctx) error {
+		c.Locals(actorKey, entity.Actor{UserID: 1, Role: constant.RoleMember})
+		return c.Next()
+	}, RequireAuth(), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/anonymous", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/member", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -5,8 +5,9 @@ import "github.com/gofiber/fiber/v2"
 func CORS() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		c.Set("Access-Control-Allow-Origin", "*")
-		c.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, DELETE")
-		c.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, PATCH, DELETE")
+		c.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, Idempotency-Key")
+		c.Set("Access-Control-Expose-Headers", "ETag, Location, Idempotent-Replayed")
 		if c.Method() == "OPTIONS" {
 			return c.SendStatus(fiber.StatusOK)
 		}
@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
 	"User-Post-Backend/infra/logger"
 	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/i18n"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,6 +17,42 @@ type AppError struct {
 	Message string `json:"message"`
 }
 
+const (
+	codeValidation = "VALIDATION_FAILED"
+	codeForbidden  = "FORBIDDEN"
+	codeInternal   = "INTERNAL_ERROR"
+	codeBadRequest = "BAD_REQUEST"
+)
+
+// fiberCodes names the errors fiber itself returns, e.g. for a path no route
+// matches or a body over the size limit. Other client errors from fiber are
+// reported as BAD_REQUEST and server errors as INTERNAL_ERROR.
+var fiberCodes = map[int]string{
+	fiber.StatusBadRequest:                  codeBadRequest,
+	fiber.StatusNotFound:                    "ROUTE_NOT_FOUND",
+	fiber.StatusMethodNotAllowed:            "METHOD_NOT_ALLOWED",
+	fiber.StatusRequestTimeout:              "REQUEST_TIMEOUT",
+	fiber.StatusRequestEntityTooLarge:       "REQUEST_ENTITY_TOO_LARGE",
+	fiber.StatusUnprocessableEntity:         "UNPROCESSABLE_ENTITY",
+	fiber.StatusRequestHeaderFieldsTooLarge: "REQUEST_HEADER_FIELDS_TOO_LARGE",
+	fiber.StatusServiceUnavailable:          "SERVICE_UNAVAILABLE",
+}
+
+// kindStatus maps every error kind of the domain catalog to its HTTP status.
+var kindStatus = []struct {
+	kind   error
+	status int
+}{
+	{constant.ErrBadRequest, fiber.StatusBadRequest},
+	{constant.ErrUnauthorized, fiber.StatusUnauthorized},
+	{constant.ErrForbidden, fiber.StatusForbidden},
+	{constant.ErrNotFound, fiber.StatusNotFound},
+	{constant.ErrConflict, fiber.StatusConflict},
+	{constant.ErrValidation, fiber.StatusUnprocessableEntity},
+	{constant.ErrUnsupported, fiber.StatusUnsupportedMediaType},
+	{constant.ErrPrecondition, fiber.StatusPreconditionFailed},
+}
+
 func ErrorHandlerMiddleware(c *fiber.Ctx) error {
 	err := c.Next()
 	if err != nil {
@@ -23,25 +61,73 @@ func ErrorHandlerMiddleware(c *fiber.Ctx) error {
 	return nil
 }
 
+// HandleError is both the error middleware's and fiber's error handler, so
+// every error reaches the client through helpers.RenderError.
 func HandleError(c *fiber.Ctx, err error) error {
-	if strings.Contains(err.Error(), constant.ErrForeignKey) {
+	language := helpers.Language(c)
+	status, appError := ResolveError(err, language)
+
+	var fields []helpers.FieldError
+	var validationError *helpers.ValidationError
+	if errors.As(err, &validationError) {
+		fields = validationError.Localize(language)
+	} else {
 		logger.Errorln(err)
+	}
+	return helpers.RenderError(c, status, appError.Code, appError.Message, fields)
+}
 
-		return c.Status(fiber.StatusBadRequest).JSON(&helpers.StandardResponse{
-			Status:  fiber.StatusBadRequest,
-			Message: "Terjadi kesalahan data tidak sesuai (foreign key). Silakan periksa data yang Anda masukkan.",
-		})
+// ResolveError picks the status and client facing code and message of err.
+// Only typed errors reach the client; anything else is reported as a 500.
+func ResolveError(err error, language string) (int, AppError) {
+	var validationError *helpers.ValidationError
+	if errors.As(err, &validationError) {
+		return fiber.StatusUnprocessableEntity, appError(language, codeValidation, "", "")
 	}
-	if strings.Contains(err.Error(), constant.ErrRecordNotFound) {
-		logger.Errorln(err)
-		return c.Status(fiber.StatusNotFound).JSON(&helpers.StandardResponse{
-			Status:  fiber.StatusNotFound,
-			Message: "Data tidak ditemukan. Silakan periksa ID yang Anda masukkan.",
-		})
-	}
-	logger.Errorln(err)
-	return c.Status(fiber.StatusInternalServerError).JSON(&helpers.StandardResponse{
-		Status:  fiber.StatusInternalServerError,
-		Message: "Terjadi kesalahan, silakan coba lagi.",
-	})
+
+	var domainError *constant.DomainError
+	if errors.As(err, &domainError) {
+		return statusOf(domainError.Kind), appError(language, domainError.Code, domainError.Message, domainError.Detail)
+	}
+	if errors.Is(err, constant.ErrForbidden) {
+		return fiber.StatusForbidden, appError(language, codeForbidden, "", "")
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return fiberError.Code, appError(language, fiberCode(fiberError.Code), fiberError.Message, "")
+	}
+	return fiber.StatusInternalServerError, appError(language, codeInternal, "", "")
+}
+
+func fiberCode(status int) string {
+	if code, ok := fiberCodes[status]; ok {
+		return code
+	}
+	if status < fiber.StatusInternalServerError {
+		return codeBadRequest
+	}
+	return codeInternal
+}
+
+func statusOf(kind error) int {
+	for _, entry := range kindStatus {
+		if errors.Is(kind, entry.kind) {
+			return entry.status
+		}
+	}
+	return fiber.StatusInternalServerError
+}
+
+// appError takes the message of code from the catalog, keeping fallback for
+// codes the catalog does not know. The detail is appended untranslated.
+func appError(language string, code string, fallback string, detail string) AppError {
+	message := fallback
+	if localized, ok := i18n.Lookup(language, "errors."+code); ok {
+		message = localized
+	}
+	if detail != "" {
+		message = strings.TrimSuffix(message, ".") + ": " + detail
+	}
+	return AppError{Code: code, Message: message}
 }
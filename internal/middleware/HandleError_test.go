@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/i18n"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorResponse(t *testing.T) {
+	t.Setenv("ERROR_FORMAT", "")
+	app := fiber.New(fiber.Config{ErrorHandler: HandleError})
+	app.Post("/register", func(c *fiber.Ctx) error {
+		var req entity.RegisterUser
+		if err := c.BodyParser(&req); err != nil {
+			return constant.ErrInvalidBody.Wrap(err)
+		}
+		return helpers.Validate(req)
+	})
+
+	// 37 runes, but 74 bytes: over bcrypt's limit although max=72 would pass.
+	body := `{"name":"Ann","email":"not-an-email","password":"` + strings.Repeat("é", 37) + `"}`
+
+	tests := []struct {
+		language string
+		message  string
+		fields   []helpers.FieldError
+	}{
+		{
+			language: "en",
+			message:  "The data you sent is invalid. Please check it again.",
+			fields: []helpers.FieldError{
+				{Field: "email", Rule: "email", Message: "must be a valid email address"},
+				{Field: "password", Rule: "max_bytes", Param: "72", Message: "must be at most 72 bytes"},
+			},
+		},
+		{
+			language: "id",
+			message:  "Data yang Anda kirim tidak valid. Silakan periksa kembali.",
+			fields: []helpers.FieldError{
+				{Field: "email", Rule: "email", Message: "harus berupa alamat email yang valid"},
+				{Field: "password", Rule: "max_bytes", Param: "72", Message: "maksimal 72 byte"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.language, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, "/register", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			req.Header.Set(fiber.HeaderAcceptLanguage, test.language)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+			var payload struct {
+				Status  int                  `json:"status"`
+				Code    string               `json:"code"`
+				Message string               `json:"message"`
+				Errors  []helpers.FieldError `json:"errors"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+			assert.Equal(t, fiber.StatusUnprocessableEntity, payload.Status)
+			assert.Equal(t, "VALIDATION_FAILED", payload.Code)
+			assert.Equal(t, test.message, payload.Message)
+			assert.Equal(t, test.fields, payload.Errors)
+		})
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	t.Setenv("DEFAULT_LANGUAGE", "en")
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"email taken", constant.ErrEmailTaken.Wrap(errors.New("duplicate key")), fiber.StatusConflict, "EMAIL_TAKEN", "Email is already registered."},
+		{"foreign key", constant.ErrInvalidRef, fiber.StatusBadRequest, "INVALID_REFERENCE", ""},
+		{"not found", fmt.Errorf("load user: %w", constant.ErrRecordNotFound), fiber.StatusNotFound, "NOT_FOUND", ""},
+		{"invalid token", constant.ErrInvalidToken, fiber.StatusUnauthorized, "INVALID_TOKEN", ""},
+		{"inactive account", constant.ErrAccountInactive, fiber.StatusForbidden, "ACCOUNT_INACTIVE", ""},
+		{"forbidden", constant.ErrForbidden, fiber.StatusForbidden, "FORBIDDEN", ""},
+		{"version mismatch", constant.ErrVersionMismatch, fiber.StatusPreconditionFailed, "VERSION_MISMATCH", ""},
+		{"unsupported patch", constant.ErrUnsupportedPatch, fiber.StatusUnsupportedMediaType, "UNSUPPORTED_PATCH", ""},
+		{"concurrent write", constant.ErrConcurrentWrite, fiber.StatusConflict, "CONCURRENT_UPDATE", ""},
+		{
+			"detail", constant.ErrBatchTooLarge.WithDetail("at most %d posts are allowed", 2),
+			fiber.StatusUnprocessableEntity, "BATCH_TOO_LARGE", "The batch has too many operations: at most 2 posts are allowed",
+		},
+		{"validation", &helpers.ValidationError{}, fiber.StatusUnprocessableEntity, "VALIDATION_FAILED", ""},
+		{"fiber error", fiber.ErrMethodNotAllowed, fiber.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", ""},
+		{"unknown route", fiber.ErrNotFound, fiber.StatusNotFound, "ROUTE_NOT_FOUND", "The requested endpoint does not exist."},
+		{"other fiber client error", fiber.ErrTooManyRequests, fiber.StatusTooManyRequests, "BAD_REQUEST", ""},
+		{"other fiber server error", fiber.ErrBadGateway, fiber.StatusBadGateway, "INTERNAL_ERROR", ""},
+		{"unknown error", errors.New("pq: password authentication failed"), fiber.StatusInternalServerError, "INTERNAL_ERROR", "Something went wrong, please try again."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, appError := ResolveError(test.err, "en")
+			assert.Equal(t, test.status, status)
+			assert.Equal(t, test.code, appError.Code)
+			if test.message != "" {
+				assert.Equal(t, test.message, appError.Message)
+			}
+		})
+	}
+}
+
+func TestCatalogsCoverEveryErrorCode(t *testing.T) {
+	codes := []string{codeValidation, codeForbidden, codeInternal, codeBadRequest}
+	for _, err := range constant.Registered() {
+		codes = append(codes, err.Code)
+	}
+	for _, code := range fiberCodes {
+		codes = append(codes, code)
+	}
+
+	for _, language := range i18n.Languages() {
+		for _, code := range codes {
+			assert.True(t, i18n.Has(language, "errors."+code), "%s catalog has no message for %s", language, code)
+		}
+	}
+}
+
+func TestUnknownRouteResponse(t *testing.T) {
+	t.Setenv("ERROR_FORMAT", "")
+	app := fiber.New(fiber.Config{ErrorHandler: HandleError})
+	app.Get("/posts", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		method  string
+		path    string
+		status  int
+		code    string
+		message string
+	}{
+		{fiber.MethodGet, "/nowhere", fiber.StatusNotFound, "ROUTE_NOT_FOUND", "Endpoint yang diminta tidak ada."},
+		{fiber.MethodPut, "/posts", fiber.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Endpoint ini tidak mendukung metode permintaan tersebut."},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set(fiber.HeaderAcceptLanguage, "id")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, test.status, resp.StatusCode)
+
+		var payload helpers.StandardResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		assert.Equal(t, test.code, payload.Code)
+		assert.Equal(t, test.message, payload.Message)
+	}
+}
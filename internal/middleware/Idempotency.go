@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/infra/logger"
+	"User-Post-Backend/internal/constant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyPendingTimeout = time.Minute
+)
+
+// replayedHeaders are the response headers stored with the body, so a replay
+// looks like the original response.
+var replayedHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderContentLanguage,
+	fiber.HeaderLocation,
+	fiber.HeaderETag,
+}
+
+// idempotentResponse is what is kept in Redis per key. A pending entry marks a
+// request that is still running.
+type idempotentResponse struct {
+	Fingerprint string            `json:"fingerprint"`
+	Pending     bool              `json:"pending,omitempty"`
+	Status      int               `json:"status,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key safe to retry.
+// The first successful response is stored for window and replayed for every
+// retry with the same key and body; the same key with a different method,
+// path or body is rejected with 409. Failed requests are not stored, so they
+// can be retried with the same key. Keys are scoped to the caller, so it has
+// to run after Authenticate.
+func Idempotency(cache *infra.RedisClient, window time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if c.Method() != fiber.MethodPost || key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return constant.ErrInvalidIdempotencyKey
+		}
+
+		cacheKey := idempotencyCacheKey(c, key)
+		fingerprint := requestFingerprint(c)
+
+		pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint, Pending: true})
+		acquired, err := cache.SetNX(cacheKey, string(pending), idempotencyPendingTimeout)
+		if err != nil {
+			// Without Redis the request still goes through, only unprotected.
+			logger.Errorln(err)
+			return c.Next()
+		}
+		if !acquired {
+			return replay(c, cache, cacheKey, fingerprint)
+		}
+
+		if err := c.Next(); err != nil {
+			cache.Delete(cacheKey)
+			return err
+		}
+
+		response := c.Response()
+		if response.StatusCode() >= fiber.StatusInternalServerError {
+			cache.Delete(cacheKey)
+			return nil
+		}
+		stored := idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      response.StatusCode(),
+			Headers:     make(map[string]string, len(replayedHeaders)),
+			Body:        append([]byte(nil), response.Body()...),
+		}
+		for _, header := range replayedHeaders {
+			if value := c.GetRespHeader(header); value != "" {
+				stored.Headers[header] = value
+			}
+		}
+		data, err := json.Marshal(stored)
+		if err == nil {
+			err = cache.SetTTL(cacheKey, string(data), window)
+		}
+		if err != nil {
+			logger.Errorln(err)
+			cache.Delete(cacheKey)
+		}
+		return nil
+	}
+}
+
+// replay answers with the response stored under cacheKey.
+func replay(c *fiber.Ctx, cache *infra.RedisClient, cacheKey string, fingerprint string) error {
+	data, err := cache.Get(cacheKey)
+	if err != nil {
+		return err
+	}
+	if data == "" {
+		// The first request failed and released the key in the meantime.
+		return constant.ErrIdempotencyInProgress
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return err
+	}
+	if stored.Fingerprint != fingerprint {
+		return constant.ErrIdempotencyKeyReused
+	}
+	if stored.Pending {
+		return constant.ErrIdempotencyInProgress
+	}
+
+	for header, value := range stored.Headers {
+		c.Set(header, value)
+	}
+	c.Set(HeaderIdempotentReplayed, "true")
+	return c.Status(stored.Status).Send(stored.Body)
+}
+
+// idempotencyCacheKey scopes key to the caller, so clients cannot replay each
+// other's responses.
+func idempotencyCacheKey(c *fiber.Ctx, key string) string {
+	caller := "anonymous"
+	if actor, ok := CurrentActor(c); ok {
+		caller = strconv.FormatUint(actor.UserID, 10)
+	}
+	return "idempotency:" + caller + ":" + key
+}
+
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idempotencyTest is an app with one idempotent POST /posts route that counts
+// how often its handler runs. The handler answers according to the body:
+// "fail" returns a domain error, "crash" a 500 response, "wait" blocks until
+// release is closed, anything else creates a post.
+type idempotencyTest struct {
+	app     *fiber.App
+	redis   *miniredis.Miniredis
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func newIdempotencyTest(t *testing.T) *idempotencyTest {
+	test := &idempotencyTest{redis: miniredis.RunT(t), release: make(chan struct{})}
+	cache := infra.NewRedisClientAt(test.redis.Addr())
+
+	test.app = fiber.New(fiber.Config{ErrorHandler: HandleError})
+	test.app.Use(func(c *fiber.Ctx) error {
+		if id, err := strconv.ParseUint(c.Get("X-User"), 10, 64); err == nil {
+			c.Locals(actorKey, entity.Actor{UserID: id, Role: constant.RoleMember})
+		}
+		return c.Next()
+	})
+	test.app.Post("/posts", Idempotency(cache, time.Hour), func(c *fiber.Ctx) error {
+		calls := test.calls.Add(1)
+		switch string(c.Body()) {
+		case "fail":
+			return constant.ErrInvalidBody
+		case "crash":
+			return c.Status(fiber.StatusInternalServerError).SendString("crashed")
+		case "wait":
+			<-test.release
+		}
+		c.Location("/posts/" + strconv.Itoa(int(calls)))
+		return c.Status(fiber.StatusCreated).SendString("post " + strconv.Itoa(int(calls)))
+	})
+	return test
+}
+
+func (test *idempotencyTest) post(t *testing.T, user string, key string, body string) (*http.Response, string) {
+	req := httptest.NewRequest(fiber.MethodPost, "/posts", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	resp, err := test.app.Test(req, -1)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func errorCode(t *testing.T, body string) string {
+	var payload helpers.StandardResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &payload), body)
+	return payload.Code
+}
+
+func TestIdempotencyReplaysTheFirstResponse(t *testing.T) {
+	test := newIdempotencyTest(t)
+
+	first, firstBody := test.post(t, "1", "key-1", "hello")
+	assert.Equal(t, fiber.StatusCreated, first.StatusCode)
+	assert.Equal(t, "post 1", firstBody)
+	assert.Empty(t, first.Header.Get(HeaderIdempotentReplayed))
+
+	retry, retryBody := test.post(t, "1", "key-1", "hello")
+	assert.Equal(t, fiber.StatusCreated, retry.StatusCode)
+	assert.Equal(t, "post 1", retryBody)
+	assert.Equal(t, "/posts/1", retry.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, "true", retry.Header.Get(HeaderIdempotentReplayed))
+	assert.Equal(t, int32(1), test.calls.Load(), "the handler runs once")
+
+	assert.True(t, test.redis.Exists("idempotency:1:key-1"))
+	assert.Greater(t, test.redis.TTL("idempotency:1:key-1"), 59*time.Minute)
+}
+
+func TestIdempotencyRejectsAReusedKey(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.post(t, "1", "key-1", "hello")
+
+	resp, body := test.post(t, "1", "key-1", "goodbye")
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errorCode(t, body))
+	assert.Equal(t, int32(1), test.calls.Load())
+}
+
+func TestIdempotencyScopesKeysToTheCaller(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.post(t, "1", "key-1", "hello")
+
+	resp, body := test.post(t, "2", "key-1", "hello")
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "post 2", body)
+	assert.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	for _, body := range []string{"fail", "crash"} {
+		t.Run(body, func(t *testing.T) {
+			test := newIdempotencyTest(t)
+			test.post(t, "1", "key-1", body)
+			assert.False(t, test.redis.Exists("idempotency:1:key-1"), "the key is released")
+
+			resp, _ := test.post(t, "1", "key-1", body)
+			assert.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+			assert.Equal(t, int32(2), test.calls.Load(), "the retry runs the handler again")
+		})
+	}
+}
+
+func TestIdempotencyRejectsARequestInFlight(t *testing.T) {
+	test := newIdempotencyTest(t)
+
+	// The first request runs on its own goroutine, which must not stop the
+	// test, so it only hands its body back.
+	done := make(chan string)
+	go func() {
+		req := httptest.NewRequest(fiber.MethodPost, "/posts", strings.NewReader("wait"))
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		req.Header.Set("X-User", "1")
+		var body []byte
+		if resp, err := test.app.Test(req, -1); err == nil {
+			body, _ = io.ReadAll(resp.Body)
+		}
+		done <- string(body)
+	}()
+	require.Eventually(t, func() bool { return test.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	resp, body := test.post(t, "1", "key-1", "wait")
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "IDEMPOTENCY_IN_PROGRESS", errorCode(t, body))
+
+	close(test.release)
+	assert.Equal(t, "post 1", <-done)
+	resp, body = test.post(t, "1", "key-1", "wait")
+	assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+	assert.Equal(t, "post 1", body)
+	assert.Equal(t, int32(1), test.calls.Load())
+}
+
+func TestIdempotencyWithoutAKey(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.post(t, "1", "", "hello")
+	test.post(t, "1", "", "hello")
+	assert.Equal(t, int32(2), test.calls.Load())
+
+	resp, body := test.post(t, "1", strings.Repeat("k", maxIdempotencyKeyLength+1), "hello")
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "INVALID_IDEMPOTENCY_KEY", errorCode(t, body))
+}
+
+func TestIdempotencyWithoutRedis(t *testing.T) {
+	test := newIdempotencyTest(t)
+	test.redis.Close()
+
+	resp, body := test.post(t, "1", "key-1", "hello")
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "post 1", body)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"User-Post-Backend/internal/constant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authorize is declared per route and lets the request through only when the
+// caller's role grants every listed permission.
+func Authorize(permissions ...constant.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, ok := CurrentActor(c)
+		if !ok {
+			return constant.ErrAuthRequired
+		}
+		for _, permission := range permissions {
+			if !actor.Can(permission) {
+				return constant.ErrForbidden
+			}
+		}
+		return c.Next()
+	}
+}
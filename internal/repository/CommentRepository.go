@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment entity.Comment) (entity.Comment, error)
+	GetByID(id uint64) (entity.Comment, error)
+	GetByPost(postID uint64, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error)
+	GetThreads(postID uint64, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error)
+	GetReplies(rootIDs []uint64) ([]entity.Comment, error)
+	Update(id uint64, content string) (entity.Comment, error)
+	Delete(id uint64) error
+	Purge(before time.Time) (int64, error)
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(comment entity.Comment) (entity.Comment, error) {
+	if err := r.postExists(comment.PostID); err != nil {
+		return comment, err
+	}
+	if err := r.db.Create(&comment).Error; err != nil {
+		return comment, translateError(err)
+	}
+	return comment, nil
+}
+
+func (r *commentRepository) GetByID(id uint64) (entity.Comment, error) {
+	var comment entity.Comment
+	err := r.db.Where("post_id IN (SELECT id FROM posts WHERE deleted_at IS NULL)").First(&comment, id).Error
+	if err != nil {
+		return comment, translateError(err)
+	}
+	return comment, nil
+}
+
+// GetByPost pages through every comment of the post, replies included.
+func (r *commentRepository) GetByPost(postID uint64, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error) {
+	if err := r.postExists(postID); err != nil {
+		return nil, entity.PageMeta{}, err
+	}
+	query := r.db.Model(&entity.Comment{}).Where("post_id = ?", postID)
+	return paginate(query, page, nil, func(comment entity.Comment) uint64 {
+		return comment.ID
+	})
+}
+
+// GetThreads pages through the top level comments of the post only.
+func (r *commentRepository) GetThreads(postID uint64, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error) {
+	if err := r.postExists(postID); err != nil {
+		return nil, entity.PageMeta{}, err
+	}
+	query := r.db.Model(&entity.Comment{}).Where("post_id = ? AND parent_id IS NULL", postID)
+	return paginate(query, page, nil, func(comment entity.Comment) uint64 {
+		return comment.ID
+	})
+}
+
+// GetReplies loads every reply below the given comments, at any depth, oldest
+// first, in one query.
+func (r *commentRepository) GetReplies(rootIDs []uint64) ([]entity.Comment, error) {
+	replies := []entity.Comment{}
+	if len(rootIDs) == 0 {
+		return replies, nil
+	}
+	err := r.db.Raw(`WITH RECURSIVE replies AS (
+		SELECT * FROM comments WHERE parent_id IN ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.* FROM comments c JOIN replies r ON c.parent_id = r.id WHERE c.deleted_at IS NULL
+	) SELECT * FROM replies ORDER BY id`, rootIDs).Scan(&replies).Error
+	return replies, translateError(err)
+}
+
+func (r *commentRepository) Update(id uint64, content string) (entity.Comment, error) {
+	result := r.db.Model(&entity.Comment{}).Where("id = ?", id).Update("content", content)
+	if err := checkAffected(result); err != nil {
+		return entity.Comment{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Delete soft-deletes the comment together with every reply below it.
+func (r *commentRepository) Delete(id uint64) error {
+	result := r.db.Exec(`WITH RECURSIVE subtree AS (
+		SELECT id FROM comments WHERE id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.id FROM comments c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+	) UPDATE comments SET deleted_at = NOW() WHERE id IN (SELECT id FROM subtree)`, id)
+	return checkAffected(result)
+}
+
+func checkAffected(result *gorm.DB) error {
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return constant.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *commentRepository) Purge(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("deleted_at < ?", before).Delete(&entity.Comment{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// postExists keeps comments of soft-deleted posts out of reach; they come
+// back when the post is restored.
+func (r *commentRepository) postExists(postID uint64) error {
+	return translateError(r.db.Select("id").First(&entity.Post{}, postID).Error)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+
+	"User-Post-Backend/internal/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres SQLSTATE codes the repositories translate into domain errors.
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgStringTooLong        = "22001"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// constraintErrors maps named unique constraints to a more specific error
+// than the generic duplicate one.
+var constraintErrors = map[string]*constant.DomainError{
+	"users_email_key": constant.ErrEmailTaken,
+}
+
+// translateError turns GORM and Postgres errors into the domain error catalog
+// so callers never need to inspect driver messages.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return constant.ErrRecordNotFound.Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		if specific, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return specific.Wrap(err)
+		}
+		return constant.ErrDuplicate.Wrap(err)
+	case pgForeignKeyViolation:
+		return constant.ErrInvalidRef.Wrap(err)
+	case pgNotNullViolation, pgCheckViolation, pgStringTooLong:
+		return constant.ErrConstraint.Wrap(err)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return constant.ErrConcurrentWrite.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	plain := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"record not found", gorm.ErrRecordNotFound, constant.ErrRecordNotFound},
+		{"wrapped record not found", fmt.Errorf("load post: %w", gorm.ErrRecordNotFound), constant.ErrRecordNotFound},
+		{"email taken", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"}, constant.ErrEmailTaken},
+		{"other unique constraint", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "tags_name_key"}, constant.ErrDuplicate},
+		{"foreign key", &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "posts_user_id_fkey"}, constant.ErrInvalidRef},
+		{"not null", &pgconn.PgError{Code: pgNotNullViolation}, constant.ErrConstraint},
+		{"check", &pgconn.PgError{Code: pgCheckViolation}, constant.ErrConstraint},
+		{"string too long", &pgconn.PgError{Code: pgStringTooLong}, constant.ErrConstraint},
+		{"serialization failure", &pgconn.PgError{Code: pgSerializationFailure}, constant.ErrConcurrentWrite},
+		{"deadlock", &pgconn.PgError{Code: pgDeadlockDetected}, constant.ErrConcurrentWrite},
+		{"domain error", constant.ErrVersionMismatch, constant.ErrVersionMismatch},
+		{"unknown error", plain, plain},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := translateError(test.err)
+			if test.want == nil {
+				assert.NoError(t, got)
+				return
+			}
+			assert.True(t, errors.Is(got, test.want), "got %v", got)
+		})
+	}
+}
+
+func TestTranslateErrorKeepsKindAndCause(t *testing.T) {
+	cause := &pgconn.PgError{Code: pgForeignKeyViolation}
+	err := translateError(fmt.Errorf("insert post: %w", cause))
+
+	assert.True(t, errors.Is(err, constant.ErrBadRequest), "the kind picks the status")
+	var pgErr *pgconn.PgError
+	assert.True(t, errors.As(err, &pgErr), "the driver error stays available for logs")
+
+	unknown := &pgconn.PgError{Code: "XX000"}
+	assert.Same(t, unknown, translateError(unknown))
+}
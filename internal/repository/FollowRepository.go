@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository interface {
+	Follow(follow entity.Follow) error
+	Unfollow(follow entity.Follow) error
+	GetFollowers(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error)
+	GetFollowing(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error)
+}
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// Follow is idempotent: following a user again keeps the first follow.
+func (r *followRepository) Follow(follow entity.Follow) error {
+	if err := r.db.Select("id").First(&entity.User{}, follow.FolloweeID).Error; err != nil {
+		return translateError(err)
+	}
+	return translateError(r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error)
+}
+
+func (r *followRepository) Unfollow(follow entity.Follow) error {
+	if err := r.db.Select("id").First(&entity.User{}, follow.FolloweeID).Error; err != nil {
+		return translateError(err)
+	}
+	return translateError(r.db.Where("follower_id = ? AND followee_id = ?", follow.FollowerID, follow.FolloweeID).
+		Delete(&entity.Follow{}).Error)
+}
+
+func (r *followRepository) GetFollowers(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error) {
+	return r.related(userID, page, "follower_id", "followee_id")
+}
+
+func (r *followRepository) GetFollowing(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error) {
+	return r.related(userID, page, "followee_id", "follower_id")
+}
+
+// related pages through the users in column of the follows where by is the
+// given user, e.g. follower_id by followee_id for their followers.
+func (r *followRepository) related(userID uint64, page entity.PageRequest, column string, by string) ([]entity.User, entity.PageMeta, error) {
+	if err := r.db.Select("id").First(&entity.User{}, userID).Error; err != nil {
+		return nil, entity.PageMeta{}, translateError(err)
+	}
+
+	ids := r.db.Model(&entity.Follow{}).Select(column).Where(by+" = ?", userID)
+	return paginate(r.db.Model(&entity.User{}).Where("id IN (?)", ids), page, nil, func(user entity.User) uint64 {
+		return user.ID
+	})
+}
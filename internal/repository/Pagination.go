@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paginate runs query newest first by id, either with limit/offset or with a
+// keyset cursor, and fills the page metadata including the total row count.
+// An explicit sort is applied before the id tie-breaker and only supports
+// offset paging, so no cursors are returned for it. Scopes such as preloads
+// apply to the page query only, not to the count.
+func paginate[T any](query *gorm.DB, page entity.PageRequest, sort []clause.OrderByColumn, idOf func(T) uint64, scopes ...func(*gorm.DB) *gorm.DB) ([]T, entity.PageMeta, error) {
+	meta := entity.PageMeta{Limit: page.Limit}
+	if err := query.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		return nil, meta, translateError(err)
+	}
+
+	pageQuery := query.Session(&gorm.Session{}).Scopes(scopes...).Limit(page.Limit + 1)
+	switch {
+	case page.Cursor != "" && page.Backward:
+		pageQuery = pageQuery.Where("id > ?", page.CursorID).Order("id ASC")
+	case page.Cursor != "":
+		pageQuery = pageQuery.Where("id < ?", page.CursorID).Order("id DESC")
+	default:
+		offset := page.Offset
+		meta.Offset = &offset
+		for _, column := range sort {
+			pageQuery = pageQuery.Order(column)
+		}
+		pageQuery = pageQuery.Order("id DESC").Offset(page.Offset)
+	}
+
+	items := []T{}
+	if err := pageQuery.Find(&items).Error; err != nil {
+		return nil, meta, translateError(err)
+	}
+
+	hasMore := len(items) > page.Limit
+	if hasMore {
+		items = items[:page.Limit]
+	}
+	if page.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 || len(sort) > 0 {
+		return items, meta, nil
+	}
+
+	first, last := idOf(items[0]), idOf(items[len(items)-1])
+	if page.Backward {
+		meta.NextCursor = helpers.EncodeCursor(last, false)
+		if hasMore {
+			meta.PrevCursor = helpers.EncodeCursor(first, true)
+		}
+		return items, meta, nil
+	}
+	if hasMore {
+		meta.NextCursor = helpers.EncodeCursor(last, false)
+	}
+	if page.Cursor != "" || page.Offset > 0 {
+		meta.PrevCursor = helpers.EncodeCursor(first, true)
+	}
+	return items, meta, nil
+}
@@ -1,68 +1,373 @@
 package repository
 
 import (
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
+	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostRepository interface {
-	Create(post entity.CreatePost) error
-	CreatePosts(posts []entity.Post) error
-	GetAll() ([]entity.Post, error)
-	GetByID(id uint64) (entity.Post, error)
-	Update(post entity.UpdatePost) error
-	Delete(id uint64) error
+	Create(post entity.CreatePost) (entity.Post, error)
+	CreatePosts(posts []entity.Post) ([]entity.Post, error)
+	GetAll(page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	GetByUserID(userID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	GetByID(id uint64, include entity.PostInclude) (entity.Post, error)
+	GetByIDs(ids []uint64) ([]entity.Post, error)
+	GetFeed(followerID uint64, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	GetByTag(tagID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	Search(search entity.PostSearch, page entity.PageRequest) ([]entity.PostSearchResult, entity.PageMeta, error)
+	Update(post entity.UpdatePost, editorID uint64, version uint64) (entity.Post, error)
+	Rollback(postID uint64, revision entity.PostRevision, editorID uint64) (entity.Post, error)
+	UpdateOwner(id uint64, userID uint64, version uint64) error
+	Delete(id uint64, version uint64) error
+	GetDeletedByID(id uint64) (entity.Post, error)
+	Restore(id uint64) error
+	Purge(before time.Time) (int64, error)
+	WriteBatch(ops []entity.PostBatchOperation, editorID uint64) ([]entity.Post, int, error)
 }
 
 type postRepository struct {
 	db *gorm.DB
+	// insertBatchSize caps the rows of one INSERT statement in bulk creates.
+	insertBatchSize int
 }
 
-func NewPostRepository(db *gorm.DB) PostRepository {
-	return &postRepository{db: db}
+func NewPostRepository(db *gorm.DB, insertBatchSize int) PostRepository {
+	return &postRepository{db: db, insertBatchSize: insertBatchSize}
 }
 
-func (r *postRepository) Create(post entity.CreatePost) error {
+func (r *postRepository) Create(post entity.CreatePost) (entity.Post, error) {
 	newPost := entity.Post{
 		Title:   post.Title,
 		Content: post.Content,
 		UserID:  post.UserID,
 	}
-	return r.db.Create(&newPost).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newPost).Error; err != nil {
+			return err
+		}
+		tags, err := setPostTags(tx, newPost.ID, &post.Tags, post.Content)
+		newPost.Tags = tags
+		return err
+	})
+	return newPost, translateError(err)
+}
+
+func (r *postRepository) GetAll(page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	filtered := applyFilters(r.db.Model(&entity.Post{}), query)
+	return paginate(filtered, page, sortColumns(query), func(post entity.Post) uint64 {
+		return post.ID
+	}, postIncludes(include))
 }
 
-func (r *postRepository) GetAll() ([]entity.Post, error) {
+func (r *postRepository) GetByUserID(userID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	if err := r.db.Select("id").First(&entity.User{}, userID).Error; err != nil {
+		return nil, entity.PageMeta{}, translateError(err)
+	}
+
+	filtered := applyFilters(r.db.Model(&entity.Post{}).Where("user_id = ?", userID), query)
+	return paginate(filtered, page, sortColumns(query), func(post entity.Post) uint64 {
+		return post.ID
+	}, postIncludes(include))
+}
+
+// GetFeed pages through the posts of every user the follower follows, newest
+// first.
+func (r *postRepository) GetFeed(followerID uint64, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	followees := r.db.Model(&entity.Follow{}).Select("followee_id").Where("follower_id = ?", followerID)
+	return paginate(r.db.Model(&entity.Post{}).Where("user_id IN (?)", followees), page, nil, func(post entity.Post) uint64 {
+		return post.ID
+	}, postIncludes(include))
+}
+
+// GetByTag pages through the posts tagged with the tag.
+func (r *postRepository) GetByTag(tagID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	tagged := r.db.Model(&entity.PostTag{}).Select("post_id").Where("tag_id = ?", tagID)
+	filtered := applyFilters(r.db.Model(&entity.Post{}).Where("id IN (?)", tagged), query)
+	return paginate(filtered, page, sortColumns(query), func(post entity.Post) uint64 {
+		return post.ID
+	}, postIncludes(include))
+}
+
+func (r *postRepository) GetByID(id uint64, include entity.PostInclude) (entity.Post, error) {
+	var post entity.Post
+	if err := r.db.Scopes(postIncludes(include)).First(&post, id).Error; err != nil {
+		return post, translateError(err)
+	}
+	return post, nil
+}
+
+func (r *postRepository) GetByIDs(ids []uint64) ([]entity.Post, error) {
 	var posts []entity.Post
-	if err := r.db.Find(&posts).Error; err != nil {
-		return nil, err
+	if err := r.db.Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return posts, nil
 }
 
-func (r *postRepository) GetByID(id uint64) (entity.Post, error) {
+// Search ranks posts matching search by ts_rank, title matches weighing more
+// than content ones. The text is read with websearch_to_tsquery, so quoted
+// phrases, "or" and -excluded words work as in web search engines. Results
+// are paged by offset only, since they are not ordered by id.
+func (r *postRepository) Search(search entity.PostSearch, page entity.PageRequest) ([]entity.PostSearchResult, entity.PageMeta, error) {
+	matched := r.db.Model(&entity.Post{}).
+		Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", search.Language, search.Text)
+	ranked := func(query *gorm.DB) *gorm.DB {
+		return query.Select(
+			"posts.*, "+
+				"ts_rank(search_vector, websearch_to_tsquery(?::regconfig, ?)) AS score, "+
+				"ts_headline(?::regconfig, "+escapedContent+", websearch_to_tsquery(?::regconfig, ?), ?) AS snippet",
+			search.Language, search.Text,
+			search.Language, search.Language, search.Text, searchHeadlineOptions,
+		)
+	}
+	byScore := []clause.OrderByColumn{{Column: clause.Column{Name: "score", Raw: true}, Desc: true}}
+	return paginate(matched, page, byScore, func(result entity.PostSearchResult) uint64 {
+		return result.ID
+	}, ranked)
+}
+
+// ApplySearchLanguage checks that language names a text search
+// configuration and makes it the one posts.search_vector is built with,
+// re-indexing posts stored with another one. It runs once at startup, and
+// searches must then be made with the same language to match the index.
+func ApplySearchLanguage(db *gorm.DB, language string) error {
+	var exists bool
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", language).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("unknown text search configuration %q", language)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		// DDL takes no parameters; language is a known configuration name here.
+		literal := "'" + strings.ReplaceAll(language, "'", "''") + "'::regconfig"
+		if err := tx.Exec("ALTER TABLE posts ALTER COLUMN search_language SET DEFAULT " + literal).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE posts SET search_language = ?::regconfig WHERE search_language <> ?::regconfig", language, language).Error
+	})
+}
+
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+// escapedContent is the content HTML-escaped before ts_headline adds its
+// <mark> tags, so the snippet is safe to render as HTML.
+const escapedContent = "replace(replace(replace(replace(content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&quot;')"
+
+// postIncludes preloads related rows with one extra query per relation for
+// the whole result set, never one per post. Tags are always loaded.
+func postIncludes(include entity.PostInclude) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		query = query.Preload("Tags", tagsByName)
+		if include.Author {
+			query = query.Preload("Author")
+		}
+		return query
+	}
+}
+
+func tagsByName(query *gorm.DB) *gorm.DB {
+	return query.Order("tags.name")
+}
+
+// Update changes the post and records the result as a new revision in the
+// same transaction. It returns the post as stored. A non-zero version must
+// match the stored one.
+func (r *postRepository) Update(post entity.UpdatePost, editorID uint64, version uint64) (entity.Post, error) {
+	var updated entity.Post
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return updateWithRevision(tx, &updated, post, editorID, version, nil)
+	})
+	return updated, translateError(err)
+}
+
+// Rollback restores the title and content of an earlier revision, recorded
+// as a new revision that points back at it.
+func (r *postRepository) Rollback(postID uint64, revision entity.PostRevision, editorID uint64) (entity.Post, error) {
+	post := entity.UpdatePost{ID: postID, Title: &revision.Title, Content: &revision.Content}
+	var updated entity.Post
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return updateWithRevision(tx, &updated, post, editorID, 0, &revision.Revision)
+	})
+	return updated, translateError(err)
+}
+
+// updateWithRevision applies post and leaves the stored result in current.
+// The version is checked under the row lock, so two writers holding the same
+// version cannot both succeed.
+func updateWithRevision(tx *gorm.DB, current *entity.Post, post entity.UpdatePost, editorID uint64, version uint64, rolledBackFrom *int) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(current, post.ID).Error; err != nil {
+		return err
+	}
+	if version != 0 && current.Version != version {
+		return constant.ErrVersionMismatch
+	}
+
+	var latest int
+	err := tx.Model(&entity.PostRevision{}).Where("post_id = ?", post.ID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	// Posts written before revisions existed get their original state
+	// recorded first, so the first edit can be diffed and rolled back.
+	if latest == 0 {
+		baseline := entity.PostRevision{
+			PostID:    current.ID,
+			Revision:  1,
+			Title:     current.Title,
+			Content:   current.Content,
+			EditorID:  &current.UserID,
+			CreatedAt: current.UpdatedAt,
+		}
+		if err := tx.Create(&baseline).Error; err != nil {
+			return err
+		}
+		latest = baseline.Revision
+	}
+
+	updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+	if post.Title != nil {
+		updates["title"] = *post.Title
+		current.Title = *post.Title
+	}
+	if post.Content != nil {
+		updates["content"] = *post.Content
+		current.Content = *post.Content
+	}
+	if err := tx.Model(&entity.Post{ID: post.ID}).Updates(updates).Error; err != nil {
+		return err
+	}
+	if post.Tags != nil || post.Content != nil {
+		if _, err := setPostTags(tx, post.ID, post.Tags, current.Content); err != nil {
+			return err
+		}
+	}
+
+	err = tx.Create(&entity.PostRevision{
+		PostID:         current.ID,
+		Revision:       latest + 1,
+		Title:          current.Title,
+		Content:        current.Content,
+		EditorID:       &editorID,
+		RolledBackFrom: rolledBackFrom,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Preload("Tags", tagsByName).First(current, post.ID).Error
+}
+
+func (r *postRepository) UpdateOwner(id uint64, userID uint64, version uint64) error {
+	result := whereVersion(r.db.Model(&entity.Post{}).Where("id = ?", id), version).
+		Updates(map[string]interface{}{"user_id": userID, "version": gorm.Expr("version + 1")})
+	return checkVersioned(result, version)
+}
+
+func (r *postRepository) Delete(id uint64, version uint64) error {
+	result := whereVersion(r.db.Where("id = ?", id), version).Delete(&entity.Post{})
+	return checkVersioned(result, version)
+}
+
+func (r *postRepository) GetDeletedByID(id uint64) (entity.Post, error) {
 	var post entity.Post
-	if err := r.db.First(&post, id).Error; err != nil {
-		return post, err
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&post, id).Error; err != nil {
+		return post, translateError(err)
 	}
 	return post, nil
 }
 
-func (r *postRepository) Update(post entity.UpdatePost) error {
-	return r.db.Model(&post).Where("id = ?", post.ID).Updates(post).Error
+// Restore only succeeds while the author still exists; posts of a deleted
+// user come back through the user's restore.
+func (r *postRepository) Restore(id uint64) error {
+	result := r.db.Unscoped().Model(&entity.Post{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Where("user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)").
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return constant.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *postRepository) Purge(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("deleted_at < ?", before).Delete(&entity.Post{})
+	return result.RowsAffected, translateError(result.Error)
 }
 
-func (r *postRepository) Delete(id uint64) error {
-	return r.db.Delete(&entity.Post{}, id).Error
+// CreatePosts inserts posts with one multi-row INSERT per insertBatchSize
+// posts, all in one transaction.
+func (r *postRepository) CreatePosts(posts []entity.Post) ([]entity.Post, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&posts, r.insertBatchSize).Error; err != nil {
+			return err
+		}
+		return tagHashtags(tx, posts)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return posts, nil
 }
 
-func (r *postRepository) CreatePosts(posts []entity.Post) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		for _, post := range posts {
-			if err := tx.Create(&post).Error; err != nil {
+// WriteBatch applies ops in one transaction and returns the stored post of
+// every create and update at the index of its operation. Creates are
+// inserted together first, then updates and deletes run in order. On failure
+// nothing is written, and the index of the failing operation is returned, or
+// -1 when the failure was in the bulk insert.
+func (r *postRepository) WriteBatch(ops []entity.PostBatchOperation, editorID uint64) ([]entity.Post, int, error) {
+	posts := make([]entity.Post, len(ops))
+	failed := -1
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var created []entity.Post
+		var createdAt []int
+		for i, op := range ops {
+			if op.Op == entity.BatchCreate {
+				created = append(created, entity.Post{Title: *op.Title, Content: *op.Content, UserID: op.UserID})
+				createdAt = append(createdAt, i)
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(&created, r.insertBatchSize).Error; err != nil {
+				return err
+			}
+			if err := tagHashtags(tx, created); err != nil {
+				return err
+			}
+		}
+		for j, i := range createdAt {
+			posts[i] = created[j]
+		}
+
+		for i, op := range ops {
+			var err error
+			switch op.Op {
+			case entity.BatchUpdate:
+				post := entity.UpdatePost{ID: op.ID, Title: op.Title, Content: op.Content}
+				err = updateWithRevision(tx, &posts[i], post, editorID, op.Version, nil)
+			case entity.BatchDelete:
+				result := whereVersion(tx.Where("id = ?", op.ID), op.Version).Delete(&entity.Post{})
+				err = checkVersioned(result, op.Version)
+			}
+			if err != nil {
+				failed = i
 				return err
 			}
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, failed, translateError(err)
+	}
+	return posts, -1, nil
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type PostRevisionRepository interface {
+	GetAll(postID uint64, page entity.PageRequest) ([]entity.PostRevision, entity.PageMeta, error)
+	GetByRevision(postID uint64, revision int) (entity.PostRevision, error)
+}
+
+type postRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostRevisionRepository(db *gorm.DB) PostRevisionRepository {
+	return &postRevisionRepository{db: db}
+}
+
+func (r *postRevisionRepository) GetAll(postID uint64, page entity.PageRequest) ([]entity.PostRevision, entity.PageMeta, error) {
+	query := r.db.Model(&entity.PostRevision{}).Where("post_id = ?", postID)
+	return paginate(query, page, nil, func(revision entity.PostRevision) uint64 {
+		return revision.ID
+	})
+}
+
+func (r *postRevisionRepository) GetByRevision(postID uint64, revision int) (entity.PostRevision, error) {
+	var postRevision entity.PostRevision
+	if err := r.db.Where("post_id = ? AND revision = ?", postID, revision).First(&postRevision).Error; err != nil {
+		return postRevision, translateError(err)
+	}
+	return postRevision, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"strings"
+
+	"User-Post-Backend/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applyFilters adds the parsed filters as WHERE clauses. Columns come from the
+// whitelisted QuerySpec and are quoted by GORM; values are always bound.
+func applyFilters(query *gorm.DB, list entity.ListQuery) *gorm.DB {
+	for _, filter := range list.Filters {
+		column := clause.Column{Name: filter.Column}
+		switch filter.Op {
+		case entity.FilterEq:
+			query = query.Where(clause.Eq{Column: column, Value: filter.Value})
+		case entity.FilterContains:
+			pattern := "%" + likeEscaper.Replace(filter.Raw) + "%"
+			query = query.Where(clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{column, pattern}})
+		case entity.FilterGt:
+			query = query.Where(clause.Gt{Column: column, Value: filter.Value})
+		case entity.FilterGte:
+			query = query.Where(clause.Gte{Column: column, Value: filter.Value})
+		case entity.FilterLt:
+			query = query.Where(clause.Lt{Column: column, Value: filter.Value})
+		case entity.FilterLte:
+			query = query.Where(clause.Lte{Column: column, Value: filter.Value})
+		}
+	}
+	return query
+}
+
+func sortColumns(list entity.ListQuery) []clause.OrderByColumn {
+	columns := make([]clause.OrderByColumn, 0, len(list.Sort))
+	for _, field := range list.Sort {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Desc})
+	}
+	return columns
+}
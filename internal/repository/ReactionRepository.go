@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository interface {
+	PostExists(postID uint64) error
+	Add(reaction entity.PostReaction) (bool, error)
+	Remove(reaction entity.PostReaction) (bool, error)
+	CountByPost(postID uint64) (entity.ReactionCounts, error)
+	CountAll() (map[uint64]entity.ReactionCounts, error)
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// PostExists returns ErrRecordNotFound unless the post exists and is not
+// deleted.
+func (r *reactionRepository) PostExists(postID uint64) error {
+	return translateError(r.db.Select("id").First(&entity.Post{}, postID).Error)
+}
+
+// Add stores the reaction unless the user already reacted to the post with
+// the same type, and reports whether it was new.
+func (r *reactionRepository) Add(reaction entity.PostReaction) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+	return result.RowsAffected > 0, translateError(result.Error)
+}
+
+// Remove deletes the reaction and reports whether there was one.
+func (r *reactionRepository) Remove(reaction entity.PostReaction) (bool, error) {
+	result := r.db.Where("post_id = ? AND user_id = ? AND type = ?", reaction.PostID, reaction.UserID, reaction.Type).
+		Delete(&entity.PostReaction{})
+	return result.RowsAffected > 0, translateError(result.Error)
+}
+
+type reactionCount struct {
+	PostID uint64
+	Type   string
+	Count  int64
+}
+
+func (r *reactionRepository) CountByPost(postID uint64) (entity.ReactionCounts, error) {
+	var rows []reactionCount
+	err := r.db.Model(&entity.PostReaction{}).Select("post_id, type, COUNT(*) AS count").
+		Where("post_id = ?", postID).Group("post_id, type").Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	counts := entity.ReactionCounts{}
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// CountAll counts the reactions of every post that has any, in one query.
+func (r *reactionRepository) CountAll() (map[uint64]entity.ReactionCounts, error) {
+	var rows []reactionCount
+	err := r.db.Model(&entity.PostReaction{}).Select("post_id, type, COUNT(*) AS count").
+		Group("post_id, type").Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	counts := make(map[uint64]entity.ReactionCounts)
+	for _, row := range rows {
+		if counts[row.PostID] == nil {
+			counts[row.PostID] = entity.ReactionCounts{}
+		}
+		counts[row.PostID][row.Type] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	GetAll(page entity.PageRequest) ([]entity.TagCount, entity.PageMeta, error)
+	GetByName(name string) (entity.Tag, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// GetAll pages through the tags in use, most used first. Tags whose posts
+// were all deleted are left out.
+func (r *tagRepository) GetAll(page entity.PageRequest) ([]entity.TagCount, entity.PageMeta, error) {
+	used := r.db.Model(&entity.Tag{}).Where("EXISTS (" + livePostTags("1") + ")")
+	counted := func(query *gorm.DB) *gorm.DB {
+		return query.Select("tags.*, (" + livePostTags("COUNT(*)") + ") AS posts")
+	}
+	byPosts := []clause.OrderByColumn{{Column: clause.Column{Name: "posts", Raw: true}, Desc: true}}
+	return paginate(used, page, byPosts, func(tag entity.TagCount) uint64 {
+		return tag.ID
+	}, counted)
+}
+
+func (r *tagRepository) GetByName(name string) (entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+		return tag, translateError(err)
+	}
+	return tag, nil
+}
+
+// livePostTags selects from the links of the current tag to posts that are
+// not soft-deleted.
+func livePostTags(selected string) string {
+	return "SELECT " + selected + " FROM post_tags JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL " +
+		"WHERE post_tags.tag_id = tags.id"
+}
+
+// setPostTags replaces the tags of a post with explicit plus the hashtags of
+// content and returns them by name. When explicit is nil the explicit tags
+// the post already has are kept, so a content edit only swaps its hashtags.
+func setPostTags(tx *gorm.DB, postID uint64, explicit *[]string, content string) ([]entity.Tag, error) {
+	var names []string
+	if explicit != nil {
+		names = helpers.NormalizeTags(*explicit)
+	} else {
+		err := tx.Model(&entity.Tag{}).Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+			Where("post_tags.post_id = ? AND NOT post_tags.from_content", postID).
+			Order("tags.name").Pluck("tags.name", &names).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	fromContent := make(map[string]bool)
+	for _, hashtag := range helpers.Hashtags(content) {
+		if !slices.Contains(names, hashtag) {
+			names = append(names, hashtag)
+			fromContent[hashtag] = true
+		}
+	}
+
+	if err := tx.Where("post_id = ?", postID).Delete(&entity.PostTag{}).Error; err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]entity.Tag, len(names))
+	for i, name := range names {
+		tags[i] = entity.Tag{Name: name}
+	}
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	var stored []entity.Tag
+	if err := tx.Where("name IN ?", names).Order("name").Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	links := make([]entity.PostTag, len(stored))
+	for i, tag := range stored {
+		links[i] = entity.PostTag{PostID: postID, TagID: tag.ID, FromContent: fromContent[tag.Name]}
+	}
+	return stored, tx.Create(&links).Error
+}
+
+// tagHashtags tags new posts with the hashtags of their content.
+func tagHashtags(tx *gorm.DB, posts []entity.Post) error {
+	for i := range posts {
+		if len(helpers.Hashtags(posts[i].Content)) == 0 {
+			continue
+		}
+		tags, err := setPostTags(tx, posts[i].ID, &[]string{}, posts[i].Content)
+		if err != nil {
+			return err
+		}
+		posts[i].Tags = tags
+	}
+	return nil
+}
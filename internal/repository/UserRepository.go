@@ -1,17 +1,27 @@
 package repository
 
 import (
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	Create(user entity.CreateUser) error
-	GetAll() ([]entity.User, error)
+	Create(user entity.User) (entity.User, error)
+	GetAll(page entity.PageRequest, query entity.ListQuery) ([]entity.User, entity.PageMeta, error)
 	GetByID(id uint64) (entity.User, error)
-	Update(user entity.User) error
-	Delete(id uint64) error
+	GetByEmail(email string) (entity.User, error)
+	Autocomplete(text string, limit int) ([]entity.UserSuggestion, error)
+	Update(id uint64, changes map[string]interface{}, version uint64) (entity.User, error)
+	UpdatePassword(id uint64, passwordHash string) error
+	UpdateRole(id uint64, role string) error
+	UpdateStatus(id uint64, status string) error
+	Delete(id uint64, version uint64) ([]uint64, error)
+	Restore(id uint64) ([]uint64, error)
+	Purge(before time.Time) (int64, error)
 }
 
 type userRepository struct {
@@ -22,37 +32,139 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user entity.CreateUser) error {
-	newUser := entity.User{
-		Name: user.Name,
+// Create stores a missing email as NULL rather than an empty string, so users
+// without one do not collide in users_email_key.
+func (r *userRepository) Create(user entity.User) (entity.User, error) {
+	query := r.db
+	if user.Email == "" {
+		query = query.Omit("email")
 	}
-	return r.db.Create(&newUser).Error
+	if err := query.Create(&user).Error; err != nil {
+		return user, translateError(err)
+	}
+	return user, nil
 }
 
-func (r *userRepository) GetAll() ([]entity.User, error) {
-	var users []entity.User
-	if err := r.db.Find(&users).Error; err != nil {
-		return nil, err
-	}
-	return users, nil
+func (r *userRepository) GetAll(page entity.PageRequest, query entity.ListQuery) ([]entity.User, entity.PageMeta, error) {
+	filtered := applyFilters(r.db.Model(&entity.User{}), query)
+	return paginate(filtered, page, sortColumns(query), func(user entity.User) uint64 {
+		return user.ID
+	})
 }
 
 func (r *userRepository) GetByID(id uint64) (entity.User, error) {
 	var user entity.User
 	if err := r.db.First(&user, id).Error; err != nil {
-		return user, err
+		return user, translateError(err)
+	}
+	return user, nil
+}
+
+func (r *userRepository) GetByEmail(email string) (entity.User, error) {
+	var user entity.User
+	if err := r.db.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		return user, translateError(err)
 	}
 	return user, nil
 }
 
-func (r *userRepository) Update(user entity.User) error {
-	return r.db.Model(&user).Where("id = ?", user.ID).Updates(user).Error
+// Autocomplete finds active users whose name contains text or is similar to
+// it, most similar first. Both conditions are served by the trigram index on
+// name.
+func (r *userRepository) Autocomplete(text string, limit int) ([]entity.UserSuggestion, error) {
+	suggestions := []entity.UserSuggestion{}
+	err := r.db.Model(&entity.User{}).
+		Select("id, name, similarity(name, ?) AS similarity", text).
+		Where("status = ?", constant.UserStatusActive).
+		Where("name ILIKE ? OR name % ?", "%"+likeEscaper.Replace(text)+"%", text).
+		Order("similarity DESC, name ASC").
+		Limit(limit).
+		Find(&suggestions).Error
+	return suggestions, translateError(err)
+}
+
+// Update writes exactly the given columns, zero values included, and returns
+// the stored row. A non-zero version must match the stored one.
+func (r *userRepository) Update(id uint64, changes map[string]interface{}, version uint64) (entity.User, error) {
+	changes["version"] = gorm.Expr("version + 1")
+	result := whereVersion(r.db.Model(&entity.User{}).Where("id = ?", id), version).Updates(changes)
+	if err := checkVersioned(result, version); err != nil {
+		return entity.User{}, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *userRepository) UpdatePassword(id uint64, passwordHash string) error {
+	return translateError(r.db.Model(&entity.User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error)
+}
+
+func (r *userRepository) UpdateRole(id uint64, role string) error {
+	return translateError(r.db.Model(&entity.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"role": role, "version": gorm.Expr("version + 1")}).Error)
+}
+
+func (r *userRepository) UpdateStatus(id uint64, status string) error {
+	return translateError(r.db.Model(&entity.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "version": gorm.Expr("version + 1")}).Error)
+}
+
+// Delete soft-deletes the user and their posts and returns the IDs of those
+// posts. The posts.user_id cascade only fires on a hard delete, so the posts
+// are marked here in the same transaction with the user's exact deleted_at,
+// which lets Restore tell them apart from posts that were deleted on their
+// own.
+func (r *userRepository) Delete(id uint64, version uint64) ([]uint64, error) {
+	deletedAt := time.Now().Truncate(time.Microsecond)
+	var posts []entity.Post
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := whereVersion(tx.Model(&entity.User{}).Where("id = ?", id), version).Update("deleted_at", deletedAt)
+		if err := checkVersioned(result, version); err != nil {
+			return err
+		}
+		return translateError(tx.Model(&posts).Clauses(returningID).Where("user_id = ?", id).Update("deleted_at", deletedAt).Error)
+	})
+	return postIDs(posts), err
+}
+
+// Restore brings back the user and only the posts removed in the same
+// cascade, and returns the IDs of those posts.
+func (r *userRepository) Restore(id uint64) ([]uint64, error) {
+	var posts []entity.Post
+	err := translateError(r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&posts).Clauses(returningID).
+			Where("user_id = ? AND deleted_at = (SELECT deleted_at FROM users WHERE id = ?)", id, id).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
 
+		result := tx.Unscoped().Model(&entity.User{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constant.ErrRecordNotFound
+		}
+		return nil
+	}))
+	return postIDs(posts), err
 }
 
-func (r *userRepository) Delete(id uint64) error {
-	if err := r.db.Where("user_id = ?", id).Delete(&entity.Post{}).Error; err != nil {
-		return err
+var returningID = clause.Returning{Columns: []clause.Column{{Name: "id"}}}
+
+func postIDs(posts []entity.Post) []uint64 {
+	ids := make([]uint64, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
 	}
-	return r.db.Delete(&entity.User{}, id).Error
+	return ids
+}
+
+// Purge hard-deletes users soft-deleted before the given time; their posts go
+// with them through the posts.user_id cascade.
+func (r *userRepository) Purge(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("deleted_at < ?", before).Delete(&entity.User{})
+	return result.RowsAffected, translateError(result.Error)
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"User-Post-Backend/internal/constant"
+
+	"gorm.io/gorm"
+)
+
+// whereVersion limits a write to the expected row version; 0 means any.
+func whereVersion(query *gorm.DB, version uint64) *gorm.DB {
+	if version == 0 {
+		return query
+	}
+	return query.Where("version = ?", version)
+}
+
+// checkVersioned reports a write guarded by whereVersion that touched no row
+// as a stale version, or as not found when no version was given.
+func checkVersioned(result *gorm.DB, version uint64) error {
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if version != 0 {
+			return constant.ErrVersionMismatch
+		}
+		return constant.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+	"time"
+)
+
+type AdminUsecase interface {
+	Purge() (entity.PurgeResult, error)
+}
+
+type adminUsecase struct {
+	userRepo    repository.UserRepository
+	postRepo    repository.PostRepository
+	commentRepo repository.CommentRepository
+	retention   time.Duration
+}
+
+func NewAdminUsecase(userRepo repository.UserRepository, postRepo repository.PostRepository, commentRepo repository.CommentRepository, retention time.Duration) AdminUsecase {
+	return &adminUsecase{userRepo: userRepo, postRepo: postRepo, commentRepo: commentRepo, retention: retention}
+}
+
+// Purge hard-deletes rows that have been soft-deleted for longer than the
+// retention period. Purged rows are already absent from every cached read.
+func (a *adminUsecase) Purge() (entity.PurgeResult, error) {
+	result := entity.PurgeResult{Before: time.Now().Add(-a.retention)}
+
+	comments, err := a.commentRepo.Purge(result.Before)
+	if err != nil {
+		return result, err
+	}
+	result.Comments = comments
+
+	posts, err := a.postRepo.Purge(result.Before)
+	if err != nil {
+		return result, err
+	}
+	result.Posts = posts
+
+	users, err := a.userRepo.Purge(result.Before)
+	if err != nil {
+		return result, err
+	}
+	result.Users = users
+	return result, nil
+}
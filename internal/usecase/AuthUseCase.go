@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"User-Post-Backend/internal/repository"
+)
+
+type AuthUsecase interface {
+	Login(req entity.LoginRequest) (entity.TokenPair, error)
+	Refresh(req entity.RefreshRequest) (entity.TokenPair, error)
+}
+
+type authUsecase struct {
+	userRepo repository.UserRepository
+	tokens   *infra.JWTManager
+}
+
+func NewAuthUsecase(userRepo repository.UserRepository, tokens *infra.JWTManager) AuthUsecase {
+	return &authUsecase{userRepo: userRepo, tokens: tokens}
+}
+
+func (a *authUsecase) Login(req entity.LoginRequest) (entity.TokenPair, error) {
+	user, err := a.userRepo.GetByEmail(req.Email)
+	if err != nil || user.PasswordHash == "" || !helpers.CheckPassword(user.PasswordHash, req.Password) {
+		return entity.TokenPair{}, constant.ErrInvalidCredentials
+	}
+	if user.Status != constant.UserStatusActive {
+		return entity.TokenPair{}, constant.ErrAccountInactive
+	}
+	return a.issue(user)
+}
+
+func (a *authUsecase) Refresh(req entity.RefreshRequest) (entity.TokenPair, error) {
+	claims, err := a.tokens.Parse(req.RefreshToken, infra.RefreshToken)
+	if err != nil {
+		return entity.TokenPair{}, constant.ErrInvalidToken
+	}
+
+	// The user may have been removed or suspended since the refresh token
+	// was issued.
+	user, err := a.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return entity.TokenPair{}, constant.ErrInvalidToken
+	}
+	if user.Status != constant.UserStatusActive {
+		return entity.TokenPair{}, constant.ErrAccountInactive
+	}
+	return a.issue(user)
+}
+
+func (a *authUsecase) issue(user entity.User) (entity.TokenPair, error) {
+	accessToken, err := a.tokens.Generate(user.ID, user.Role, infra.AccessToken)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+	refreshToken, err := a.tokens.Generate(user.ID, user.Role, infra.RefreshToken)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	return entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(a.tokens.AccessTTL().Seconds()),
+	}, nil
+}
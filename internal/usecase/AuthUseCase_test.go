@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthUsecase(t *testing.T) (AuthUsecase, *infra.JWTManager, *fakeUserRepository) {
+	hash, err := helpers.HashPassword("correct horse")
+	require.NoError(t, err)
+	repo := newFakeUserRepository(
+		entity.User{ID: 1, Email: "ann@example.com", PasswordHash: hash, Role: constant.RoleMember, Status: constant.UserStatusActive},
+		entity.User{ID: 2, Email: "bob@example.com", PasswordHash: hash, Role: constant.RoleMember, Status: constant.UserStatusSuspended},
+		entity.User{ID: 3, Email: "eve@example.com", Role: constant.RoleMember, Status: constant.UserStatusActive},
+	)
+	tokens := infra.NewHMACJWTManager([]byte("test-secret"), "test", time.Minute, time.Hour)
+	return NewAuthUsecase(repo, tokens), tokens, repo
+}
+
+func TestLogin(t *testing.T) {
+	auth, tokens, _ := newTestAuthUsecase(t)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		err      error
+	}{
+		{"valid", "ann@example.com", "correct horse", nil},
+		{"wrong password", "ann@example.com", "wrong", constant.ErrInvalidCredentials},
+		{"unknown email", "nobody@example.com", "correct horse", constant.ErrInvalidCredentials},
+		{"no password set", "eve@example.com", "", constant.ErrInvalidCredentials},
+		{"suspended", "bob@example.com", "correct horse", constant.ErrAccountInactive},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pair, err := auth.Login(entity.LoginRequest{Email: test.email, Password: test.password})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			claims, err := tokens.Parse(pair.AccessToken, infra.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), claims.UserID)
+			_, err = tokens.Parse(pair.RefreshToken, infra.RefreshToken)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	auth, tokens, repo := newTestAuthUsecase(t)
+	expired := infra.NewHMACJWTManager([]byte("test-secret"), "test", -time.Minute, -time.Minute)
+	sign := func(manager *infra.JWTManager, userID uint64, tokenType infra.TokenType) string {
+		signed, err := manager.Generate(userID, constant.RoleMember, tokenType)
+		require.NoError(t, err)
+		return signed
+	}
+	repo.users[4] = entity.User{ID: 4, Role: constant.RoleAdmin, Status: constant.UserStatusActive}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"valid", sign(tokens, 4, infra.RefreshToken), nil},
+		{"access token", sign(tokens, 4, infra.AccessToken), constant.ErrInvalidToken},
+		{"expired", sign(expired, 4, infra.RefreshToken), constant.ErrInvalidToken},
+		{"malformed", "not.a.jwt", constant.ErrInvalidToken},
+		{"deleted user", sign(tokens, 9, infra.RefreshToken), constant.ErrInvalidToken},
+		{"suspended user", sign(tokens, 2, infra.RefreshToken), constant.ErrAccountInactive},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pair, err := auth.Refresh(entity.RefreshRequest{RefreshToken: test.token})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			claims, err := tokens.Parse(pair.AccessToken, infra.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, constant.RoleAdmin, claims.Role, "the new token carries the stored role")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/entity"
+	"encoding/json"
+)
+
+// cached returns the value stored at key, or calls load and stores what it
+// returns. A missing or unreadable entry only costs a trip to the database.
+func cached[T any](cache *infra.RedisClient, key string, load func() (T, error)) (T, error) {
+	if data, err := cache.Get(key); err == nil && data != "" {
+		var value T
+		if json.Unmarshal([]byte(data), &value) == nil {
+			return value, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		cache.Set(key, string(data))
+	}
+	return value, nil
+}
+
+// cachedList is cached for one page of a list read.
+func cachedList[T any](cache *infra.RedisClient, key string, load func() ([]T, entity.PageMeta, error)) ([]T, entity.PageMeta, error) {
+	page, err := cached(cache, key, func() (cachedPage[T], error) {
+		items, meta, err := load()
+		return cachedPage[T]{Items: items, Meta: meta}, err
+	})
+	if err != nil {
+		return nil, page.Meta, err
+	}
+	return page.Items, page.Meta, nil
+}
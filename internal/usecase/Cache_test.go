@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCached(t *testing.T) {
+	cache := newTestCache(t)
+	loads := 0
+	load := func() (entity.Post, error) {
+		loads++
+		return entity.Post{ID: 1, Title: "cached"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		post, err := cached(cache, "post:1", load)
+		require.NoError(t, err)
+		assert.Equal(t, "cached", post.Title)
+	}
+	assert.Equal(t, 1, loads, "the second read is served from the cache")
+
+	require.NoError(t, cache.Set("post:1", "{not json"))
+	_, err := cached(cache, "post:1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "an unreadable entry is loaded again")
+}
+
+func TestCachedListSkipsErrors(t *testing.T) {
+	cache := newTestCache(t)
+	failure := errors.New("database is down")
+	loads := 0
+	load := func() ([]entity.Post, entity.PageMeta, error) {
+		loads++
+		if loads == 1 {
+			return nil, entity.PageMeta{}, failure
+		}
+		return []entity.Post{{ID: 1}}, entity.PageMeta{Limit: 20}, nil
+	}
+
+	_, _, err := cachedList(cache, "posts:page", load)
+	assert.ErrorIs(t, err, failure)
+
+	posts, meta, err := cachedList(cache, "posts:page", load)
+	require.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, 20, meta.Limit)
+
+	_, _, err = cachedList(cache, "posts:page", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "failed loads are not cached, successful ones are")
+}
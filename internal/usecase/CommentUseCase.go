@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+	"errors"
+)
+
+type CommentUsecase interface {
+	Create(actor entity.Actor, postID uint64, comment entity.CreateComment) (entity.Comment, error)
+	GetByID(id uint64) (entity.Comment, error)
+	GetByPost(postID uint64, view string, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error)
+	Update(actor entity.Actor, id uint64, comment entity.UpdateComment) (entity.Comment, error)
+	Delete(actor entity.Actor, id uint64) error
+}
+
+type commentUsecase struct {
+	repo repository.CommentRepository
+}
+
+func NewCommentUsecase(repo repository.CommentRepository) CommentUsecase {
+	return &commentUsecase{repo: repo}
+}
+
+// Create adds a comment to the post, or a reply when a parent is given. The
+// parent must be a comment of the same post.
+func (u *commentUsecase) Create(actor entity.Actor, postID uint64, comment entity.CreateComment) (entity.Comment, error) {
+	if comment.ParentID != nil {
+		parent, err := u.repo.GetByID(*comment.ParentID)
+		if errors.Is(err, constant.ErrRecordNotFound) {
+			return entity.Comment{}, constant.ErrInvalidRef.WithDetail("parent_id does not exist")
+		}
+		if err != nil {
+			return entity.Comment{}, err
+		}
+		if parent.PostID != postID {
+			return entity.Comment{}, constant.ErrInvalidRef.WithDetail("parent_id belongs to another post")
+		}
+	}
+
+	return u.repo.Create(entity.Comment{
+		PostID:   postID,
+		ParentID: comment.ParentID,
+		UserID:   actor.UserID,
+		Content:  comment.Content,
+	})
+}
+
+func (u *commentUsecase) GetByID(id uint64) (entity.Comment, error) {
+	return u.repo.GetByID(id)
+}
+
+// GetByPost pages through every comment, newest first, in the flat view. The
+// tree view pages through top level comments instead and nests all their
+// replies below them, oldest first.
+func (u *commentUsecase) GetByPost(postID uint64, view string, page entity.PageRequest) ([]entity.Comment, entity.PageMeta, error) {
+	if view == entity.CommentViewFlat {
+		return u.repo.GetByPost(postID, page)
+	}
+
+	threads, meta, err := u.repo.GetThreads(postID, page)
+	if err != nil {
+		return nil, meta, err
+	}
+	rootIDs := make([]uint64, len(threads))
+	for i, thread := range threads {
+		rootIDs[i] = thread.ID
+	}
+	replies, err := u.repo.GetReplies(rootIDs)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	children := make(map[uint64][]entity.Comment)
+	for _, reply := range replies {
+		children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+	}
+	for i := range threads {
+		threads[i].Replies = nestReplies(threads[i].ID, children)
+	}
+	return threads, meta, nil
+}
+
+func nestReplies(parentID uint64, children map[uint64][]entity.Comment) []entity.Comment {
+	replies := children[parentID]
+	for i := range replies {
+		replies[i].Replies = nestReplies(replies[i].ID, children)
+	}
+	return replies
+}
+
+// Update is reserved for the author of the comment.
+func (u *commentUsecase) Update(actor entity.Actor, id uint64, comment entity.UpdateComment) (entity.Comment, error) {
+	existing, err := u.repo.GetByID(id)
+	if err != nil {
+		return existing, err
+	}
+	if existing.UserID != actor.UserID {
+		return existing, constant.ErrForbidden
+	}
+	return u.repo.Update(id, comment.Content)
+}
+
+// Delete removes the comment with all replies below it. Besides the author,
+// moderators and admins may delete any comment.
+func (u *commentUsecase) Delete(actor entity.Actor, id uint64) error {
+	existing, err := u.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing.UserID != actor.UserID && !actor.Can(constant.PermDeleteAnyComment) {
+		return constant.ErrForbidden
+	}
+	return u.repo.Delete(id)
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"maps"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+)
+
+// fakeUserRepository keeps users in memory. Methods a test does not need are
+// left to the embedded nil interface and panic when called.
+type fakeUserRepository struct {
+	repository.UserRepository
+	users   map[uint64]entity.User
+	deleted map[uint64]entity.User
+	// posts lists the IDs of each user's posts, which Delete and Restore
+	// report as cascaded.
+	posts map[uint64][]uint64
+}
+
+func newFakeUserRepository(users ...entity.User) *fakeUserRepository {
+	repo := &fakeUserRepository{
+		users:   make(map[uint64]entity.User, len(users)),
+		deleted: make(map[uint64]entity.User),
+		posts:   make(map[uint64][]uint64),
+	}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepository) GetByID(id uint64) (entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return entity.User{}, constant.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(email string) (entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return entity.User{}, constant.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) Delete(id uint64, version uint64) ([]uint64, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, constant.ErrRecordNotFound
+	}
+	delete(r.users, id)
+	r.deleted[id] = user
+	return r.posts[id], nil
+}
+
+func (r *fakeUserRepository) Restore(id uint64) ([]uint64, error) {
+	user, ok := r.deleted[id]
+	if !ok {
+		return nil, constant.ErrRecordNotFound
+	}
+	delete(r.deleted, id)
+	r.users[id] = user
+	return r.posts[id], nil
+}
+
+// fakePostRepository keeps posts in memory, like fakeUserRepository. Writes
+// to the posts in failing and creates titled "bad" fail as the database
+// would.
+type fakePostRepository struct {
+	repository.PostRepository
+	posts   map[uint64]entity.Post
+	nextID  uint64
+	failing map[uint64]bool
+}
+
+func newFakePostRepository(posts ...entity.Post) *fakePostRepository {
+	repo := &fakePostRepository{posts: make(map[uint64]entity.Post, len(posts)), failing: make(map[uint64]bool)}
+	for _, post := range posts {
+		if post.Version == 0 {
+			post.Version = 1
+		}
+		repo.posts[post.ID] = post
+		repo.nextID = max(repo.nextID, post.ID)
+	}
+	return repo
+}
+
+func (r *fakePostRepository) Create(post entity.CreatePost) (entity.Post, error) {
+	if post.Title == "bad" {
+		return entity.Post{}, constant.ErrConstraint
+	}
+	r.nextID++
+	created := entity.Post{ID: r.nextID, Title: post.Title, Content: post.Content, UserID: post.UserID, Version: 1}
+	r.posts[created.ID] = created
+	return created, nil
+}
+
+func (r *fakePostRepository) GetByID(id uint64, include entity.PostInclude) (entity.Post, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return entity.Post{}, constant.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+func (r *fakePostRepository) Update(post entity.UpdatePost, editorID uint64, version uint64) (entity.Post, error) {
+	current, ok := r.posts[post.ID]
+	if !ok {
+		return entity.Post{}, constant.ErrRecordNotFound
+	}
+	if r.failing[post.ID] {
+		return entity.Post{}, constant.ErrConcurrentWrite
+	}
+	if version != 0 && current.Version != version {
+		return entity.Post{}, constant.ErrVersionMismatch
+	}
+	if post.Title != nil {
+		current.Title = *post.Title
+	}
+	if post.Content != nil {
+		current.Content = *post.Content
+	}
+	current.Version++
+	r.posts[post.ID] = current
+	return current, nil
+}
+
+func (r *fakePostRepository) UpdateOwner(id uint64, userID uint64, version uint64) error {
+	current, ok := r.posts[id]
+	if !ok {
+		return constant.ErrRecordNotFound
+	}
+	if version != 0 && current.Version != version {
+		return constant.ErrVersionMismatch
+	}
+	current.UserID = userID
+	current.Version++
+	r.posts[id] = current
+	return nil
+}
+
+func (r *fakePostRepository) CreatePosts(posts []entity.Post) ([]entity.Post, error) {
+	for _, post := range posts {
+		if post.Title == "bad" {
+			return nil, constant.ErrConstraint
+		}
+	}
+	created := make([]entity.Post, 0, len(posts))
+	for _, post := range posts {
+		stored, _ := r.Create(entity.CreatePost{Title: post.Title, Content: post.Content, UserID: post.UserID})
+		created = append(created, stored)
+	}
+	return created, nil
+}
+
+func (r *fakePostRepository) GetByIDs(ids []uint64) ([]entity.Post, error) {
+	var posts []entity.Post
+	for _, id := range ids {
+		if post, ok := r.posts[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+func (r *fakePostRepository) Delete(id uint64, version uint64) error {
+	current, ok := r.posts[id]
+	if !ok {
+		return constant.ErrRecordNotFound
+	}
+	if r.failing[id] {
+		return constant.ErrConcurrentWrite
+	}
+	if version != 0 && current.Version != version {
+		return constant.ErrVersionMismatch
+	}
+	delete(r.posts, id)
+	return nil
+}
+
+// WriteBatch follows the real one: creates first, then updates and deletes
+// in order, and nothing is kept when any of them fails.
+func (r *fakePostRepository) WriteBatch(ops []entity.PostBatchOperation, editorID uint64) ([]entity.Post, int, error) {
+	saved, savedID := maps.Clone(r.posts), r.nextID
+	rollback := func(failed int, err error) ([]entity.Post, int, error) {
+		r.posts, r.nextID = saved, savedID
+		return nil, failed, err
+	}
+
+	posts := make([]entity.Post, len(ops))
+	for i, op := range ops {
+		if op.Op == entity.BatchCreate {
+			created, err := r.Create(entity.CreatePost{Title: *op.Title, Content: *op.Content, UserID: op.UserID})
+			if err != nil {
+				return rollback(-1, err)
+			}
+			posts[i] = created
+		}
+	}
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case entity.BatchUpdate:
+			posts[i], err = r.Update(entity.UpdatePost{ID: op.ID, Title: op.Title, Content: op.Content}, editorID, op.Version)
+		case entity.BatchDelete:
+			err = r.Delete(op.ID, op.Version)
+		}
+		if err != nil {
+			return rollback(i, err)
+		}
+	}
+	return posts, -1, nil
+}
+
+// fakeReactionRepository keeps reactions of the posts in posts in memory.
+type fakeReactionRepository struct {
+	repository.ReactionRepository
+	posts     map[uint64]bool
+	reactions map[entity.PostReaction]bool
+}
+
+func newFakeReactionRepository(postIDs ...uint64) *fakeReactionRepository {
+	repo := &fakeReactionRepository{posts: make(map[uint64]bool), reactions: make(map[entity.PostReaction]bool)}
+	for _, id := range postIDs {
+		repo.posts[id] = true
+	}
+	return repo
+}
+
+func (r *fakeReactionRepository) PostExists(postID uint64) error {
+	if !r.posts[postID] {
+		return constant.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *fakeReactionRepository) Add(reaction entity.PostReaction) (bool, error) {
+	if r.reactions[reaction] {
+		return false, nil
+	}
+	r.reactions[reaction] = true
+	return true, nil
+}
+
+func (r *fakeReactionRepository) Remove(reaction entity.PostReaction) (bool, error) {
+	if !r.reactions[reaction] {
+		return false, nil
+	}
+	delete(r.reactions, reaction)
+	return true, nil
+}
+
+func (r *fakeReactionRepository) CountByPost(postID uint64) (entity.ReactionCounts, error) {
+	counts := entity.ReactionCounts{}
+	for reaction := range r.reactions {
+		if reaction.PostID == postID {
+			counts[reaction.Type]++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+)
+
+// FeedStrategy builds the home feed of a user: the posts of everyone they
+// follow, newest first. Fan-out-on-read queries them on every request; a
+// strategy that precomputes feeds, e.g. in Redis sorted sets, can replace it
+// without changing FollowUsecase.
+type FeedStrategy interface {
+	Feed(userID uint64, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+}
+
+type fanOutOnReadFeed struct {
+	postRepo repository.PostRepository
+}
+
+func NewFanOutOnReadFeed(postRepo repository.PostRepository) FeedStrategy {
+	return &fanOutOnReadFeed{postRepo: postRepo}
+}
+
+func (f *fanOutOnReadFeed) Feed(userID uint64, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	return f.postRepo.GetFeed(userID, page, include)
+}
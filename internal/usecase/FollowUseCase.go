@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+)
+
+type FollowUsecase interface {
+	Follow(actor entity.Actor, userID uint64) error
+	Unfollow(actor entity.Actor, userID uint64) error
+	GetFollowers(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error)
+	GetFollowing(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error)
+	Feed(actor entity.Actor, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+}
+
+type followUsecase struct {
+	repo repository.FollowRepository
+	feed FeedStrategy
+}
+
+func NewFollowUsecase(repo repository.FollowRepository, feed FeedStrategy) FollowUsecase {
+	return &followUsecase{repo: repo, feed: feed}
+}
+
+func (u *followUsecase) Follow(actor entity.Actor, userID uint64) error {
+	if userID == actor.UserID {
+		return constant.ErrSelfFollow
+	}
+	return u.repo.Follow(entity.Follow{FollowerID: actor.UserID, FolloweeID: userID})
+}
+
+func (u *followUsecase) Unfollow(actor entity.Actor, userID uint64) error {
+	if userID == actor.UserID {
+		return constant.ErrSelfFollow
+	}
+	return u.repo.Unfollow(entity.Follow{FollowerID: actor.UserID, FolloweeID: userID})
+}
+
+func (u *followUsecase) GetFollowers(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error) {
+	return u.repo.GetFollowers(userID, page)
+}
+
+func (u *followUsecase) GetFollowing(userID uint64, page entity.PageRequest) ([]entity.User, entity.PageMeta, error) {
+	return u.repo.GetFollowing(userID, page)
+}
+
+func (u *followUsecase) Feed(actor entity.Actor, page entity.PageRequest, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	return u.feed.Feed(actor.UserID, page, include)
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/entity"
+	"fmt"
+)
+
+// cachedPage is the Redis representation of one page of a list read.
+type cachedPage[T any] struct {
+	Items []T             `json:"items"`
+	Meta  entity.PageMeta `json:"meta"`
+}
+
+// cacheKey places key under the current generation of group, so that
+// Invalidate(group) drops it together with every other key of the group.
+func cacheKey(cache *infra.RedisClient, group string, key string) string {
+	return fmt.Sprintf("%s:gen=%d:%s", group, cache.Generation(group), key)
+}
+
+// pageCacheKey derives a per-page key for a list of group; scope tells the
+// lists of one group apart, e.g. "user=5". The normalized query is part of
+// the key so different filters never share an entry.
+func pageCacheKey(cache *infra.RedisClient, group string, scope string, page entity.PageRequest, query entity.ListQuery) string {
+	return cacheKey(cache, group, fmt.Sprintf("%s:limit=%d:offset=%d:cursor=%s:%s", scope, page.Limit, page.Offset, page.Cursor, query.Key()))
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Batch checks every operation against the same rules as the single post
+// endpoints before writing anything. In atomic mode the first failing
+// operation fails the whole batch; in best effort mode failures are reported
+// per item and the rest is still written.
+func (p *postUsecase) Batch(actor entity.Actor, batch entity.PostBatch) (entity.PostBatchResult, error) {
+	if len(batch.Operations) > p.maxBatchSize {
+		return entity.PostBatchResult{}, constant.ErrBatchTooLarge.WithDetail("at most %d operations are allowed", p.maxBatchSize)
+	}
+	result := entity.PostBatchResult{Mode: batch.Mode, Items: make([]entity.PostBatchItem, len(batch.Operations))}
+	if result.Mode == "" {
+		result.Mode = entity.BatchAtomic
+	}
+
+	targets, err := p.batchTargets(batch.Operations)
+	if err != nil {
+		return entity.PostBatchResult{}, err
+	}
+	ops := batch.Operations
+	for i := range ops {
+		result.Items[i] = entity.PostBatchItem{Index: i, Op: ops[i].Op, ID: ops[i].ID}
+		result.Items[i].Err = checkBatchOperation(actor, &ops[i], targets)
+	}
+
+	if result.Mode == entity.BatchAtomic {
+		for _, item := range result.Items {
+			if item.Err != nil {
+				return entity.PostBatchResult{}, batchOperationError(item.Index, item.Err)
+			}
+		}
+		posts, failed, err := p.repo.WriteBatch(ops, actor.UserID)
+		if err != nil {
+			if failed >= 0 {
+				return entity.PostBatchResult{}, batchOperationError(failed, err)
+			}
+			return entity.PostBatchResult{}, err
+		}
+		for i := range result.Items {
+			if ops[i].Op != entity.BatchDelete {
+				result.Items[i].ID = posts[i].ID
+				result.Items[i].Post = &posts[i]
+			}
+		}
+	} else {
+		p.writeEach(actor, ops, result.Items)
+	}
+
+	for _, item := range result.Items {
+		if item.Err != nil {
+			result.Failed++
+			continue
+		}
+		result.Succeeded++
+		if item.Op != entity.BatchCreate {
+			p.cache.Delete("post:" + strconv.Itoa(int(item.ID)))
+		}
+	}
+	if result.Succeeded > 0 {
+		p.cache.Invalidate("posts")
+	}
+	return result, nil
+}
+
+// batchTargets loads every post the batch updates or deletes in one query.
+func (p *postUsecase) batchTargets(ops []entity.PostBatchOperation) (map[uint64]entity.Post, error) {
+	var ids []uint64
+	for _, op := range ops {
+		if op.Op != entity.BatchCreate && op.ID != 0 {
+			ids = append(ids, op.ID)
+		}
+	}
+	targets := make(map[uint64]entity.Post, len(ids))
+	if len(ids) == 0 {
+		return targets, nil
+	}
+	posts, err := p.repo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		targets[post.ID] = post
+	}
+	return targets, nil
+}
+
+// checkBatchOperation validates op and checks the caller may apply it.
+// Creates follow the owner rule of the single create.
+func checkBatchOperation(actor entity.Actor, op *entity.PostBatchOperation, targets map[uint64]entity.Post) error {
+	if err := helpers.Validate(*op); err != nil {
+		return err
+	}
+	if op.Op == entity.BatchCreate {
+		owner, err := postOwner(actor, op.UserID)
+		op.UserID = owner
+		return err
+	}
+
+	target, ok := targets[op.ID]
+	if !ok {
+		return constant.ErrRecordNotFound
+	}
+	permission := constant.PermUpdateAnyPost
+	if op.Op == entity.BatchDelete {
+		permission = constant.PermDeleteAnyPost
+	}
+	if target.UserID != actor.UserID && !actor.Can(permission) {
+		return constant.ErrForbidden
+	}
+	return checkVersion(target.Version, op.Version)
+}
+
+// writeEach applies the operations that passed the checks one by one, in
+// best effort mode. Creates still share a bulk insert; only when it fails are
+// they inserted one at a time to find the failing ones.
+func (p *postUsecase) writeEach(actor entity.Actor, ops []entity.PostBatchOperation, items []entity.PostBatchItem) {
+	var creates []entity.Post
+	var createdAt []int
+	for i, op := range ops {
+		if op.Op == entity.BatchCreate && items[i].Err == nil {
+			creates = append(creates, entity.Post{Title: *op.Title, Content: *op.Content, UserID: op.UserID})
+			createdAt = append(createdAt, i)
+		}
+	}
+	if len(creates) > 0 {
+		created, err := p.repo.CreatePosts(creates)
+		for j, i := range createdAt {
+			post := entity.Post{}
+			if err == nil {
+				post = created[j]
+			} else {
+				op := ops[i]
+				post, items[i].Err = p.repo.Create(entity.CreatePost{Title: *op.Title, Content: *op.Content, UserID: op.UserID})
+			}
+			if items[i].Err == nil {
+				items[i].ID = post.ID
+				items[i].Post = &post
+			}
+		}
+	}
+
+	for i, op := range ops {
+		if items[i].Err != nil {
+			continue
+		}
+		switch op.Op {
+		case entity.BatchUpdate:
+			post := entity.UpdatePost{ID: op.ID, Title: op.Title, Content: op.Content}
+			updated, err := p.repo.Update(post, actor.UserID, op.Version)
+			if err != nil {
+				items[i].Err = err
+				continue
+			}
+			items[i].Post = &updated
+		case entity.BatchDelete:
+			items[i].Err = p.repo.Delete(op.ID, op.Version)
+		}
+	}
+}
+
+// batchOperationError points err at the operation that caused it.
+func batchOperationError(index int, err error) error {
+	path := fmt.Sprintf("operations[%d]", index)
+	var validationError *helpers.ValidationError
+	if errors.As(err, &validationError) {
+		return validationError.InField(path)
+	}
+	var domainError *constant.DomainError
+	if errors.As(err, &domainError) {
+		return domainError.WithDetail("%s", path)
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
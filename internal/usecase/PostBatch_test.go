@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func text(s string) *string {
+	return &s
+}
+
+func createOp(title string) entity.PostBatchOperation {
+	return entity.PostBatchOperation{Op: entity.BatchCreate, Title: text(title), Content: text("c")}
+}
+
+func updateOp(id uint64, title string) entity.PostBatchOperation {
+	return entity.PostBatchOperation{Op: entity.BatchUpdate, ID: id, Title: text(title)}
+}
+
+func deleteOp(id uint64) entity.PostBatchOperation {
+	return entity.PostBatchOperation{Op: entity.BatchDelete, ID: id}
+}
+
+// batchPosts are post 1 owned by member and post 2 owned by user 3.
+var batchPosts = []entity.Post{
+	{ID: 1, Title: "one", UserID: 2},
+	{ID: 2, Title: "two", UserID: 3},
+}
+
+func TestBatchAtomic(t *testing.T) {
+	posts, repo := newTestPostUsecase(t, batchPosts...)
+	result, err := posts.Batch(member, entity.PostBatch{Operations: []entity.PostBatchOperation{
+		updateOp(1, "updated"),
+		createOp("created"),
+		deleteOp(1),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.BatchAtomic, result.Mode)
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Zero(t, result.Failed)
+	require.Len(t, result.Items, 3)
+	for i, item := range result.Items {
+		assert.Equal(t, i, item.Index)
+		assert.NoError(t, item.Err)
+	}
+	assert.Equal(t, "updated", result.Items[0].Post.Title)
+	assert.Equal(t, "created", result.Items[1].Post.Title)
+	assert.Equal(t, member.UserID, result.Items[1].Post.UserID)
+	assert.Equal(t, result.Items[1].Post.ID, result.Items[1].ID)
+	assert.Nil(t, result.Items[2].Post)
+
+	assert.NotContains(t, repo.posts, uint64(1))
+	assert.Contains(t, repo.posts, result.Items[1].ID)
+}
+
+func TestBatchAtomicCheckFailure(t *testing.T) {
+	posts, repo := newTestPostUsecase(t, batchPosts...)
+	_, err := posts.Batch(member, entity.PostBatch{Operations: []entity.PostBatchOperation{
+		updateOp(1, "updated"),
+		deleteOp(2),
+	}})
+
+	require.True(t, errors.Is(err, constant.ErrForbidden), "got %v", err)
+	assert.ErrorContains(t, err, "operations[1]")
+	assert.Equal(t, "one", repo.posts[1].Title)
+	assert.Contains(t, repo.posts, uint64(2))
+}
+
+func TestBatchAtomicRollback(t *testing.T) {
+	posts, repo := newTestPostUsecase(t, batchPosts...)
+	repo.failing[2] = true
+	_, err := posts.Batch(admin, entity.PostBatch{Operations: []entity.PostBatchOperation{
+		createOp("created"),
+		updateOp(1, "updated"),
+		deleteOp(2),
+	}})
+
+	require.True(t, errors.Is(err, constant.ErrConcurrentWrite), "got %v", err)
+	var domainError *constant.DomainError
+	require.True(t, errors.As(err, &domainError))
+	assert.Equal(t, "operations[2]", domainError.Detail)
+	assert.Len(t, repo.posts, 2)
+	assert.Equal(t, "one", repo.posts[1].Title)
+}
+
+func TestBatchBestEffort(t *testing.T) {
+	posts, repo := newTestPostUsecase(t, append(batchPosts, entity.Post{ID: 3, Title: "three", UserID: 2})...)
+	repo.failing[3] = true
+	result, err := posts.Batch(member, entity.PostBatch{Mode: entity.BatchBestEffort, Operations: []entity.PostBatchOperation{
+		createOp("created"),
+		updateOp(2, "updated"),
+		deleteOp(9),
+		deleteOp(3),
+		updateOp(1, "updated"),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.BatchBestEffort, result.Mode)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 3, result.Failed)
+	require.Len(t, result.Items, 5)
+	errs := []error{nil, constant.ErrForbidden, constant.ErrRecordNotFound, constant.ErrConcurrentWrite, nil}
+	for i, want := range errs {
+		if want == nil {
+			assert.NoError(t, result.Items[i].Err, "operations[%d]", i)
+			continue
+		}
+		assert.True(t, errors.Is(result.Items[i].Err, want), "operations[%d]: got %v", i, result.Items[i].Err)
+	}
+
+	assert.Contains(t, repo.posts, result.Items[0].ID)
+	assert.Equal(t, "two", repo.posts[2].Title)
+	assert.Contains(t, repo.posts, uint64(3))
+	assert.Equal(t, "updated", repo.posts[1].Title)
+}
+
+func TestBatchBestEffortCreates(t *testing.T) {
+	posts, repo := newTestPostUsecase(t)
+	result, err := posts.Batch(member, entity.PostBatch{Mode: entity.BatchBestEffort, Operations: []entity.PostBatchOperation{
+		createOp("first"),
+		createOp("bad"),
+		createOp("second"),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.True(t, errors.Is(result.Items[1].Err, constant.ErrConstraint), "got %v", result.Items[1].Err)
+	assert.Equal(t, "first", result.Items[0].Post.Title)
+	assert.Equal(t, "second", result.Items[2].Post.Title)
+	assert.Len(t, repo.posts, 2)
+}
+
+func TestBatchCreateOwner(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor entity.Actor
+		err   error
+	}{
+		{"member names another user", member, constant.ErrForbidden},
+		{"admin names another user", admin, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			posts, _ := newTestPostUsecase(t)
+			op := createOp("created")
+			op.UserID = 3
+			result, err := posts.Batch(test.actor, entity.PostBatch{Operations: []entity.PostBatchOperation{op}})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint64(3), result.Items[0].Post.UserID)
+		})
+	}
+}
+
+func TestBatchTooLarge(t *testing.T) {
+	posts, repo := newTestPostUsecase(t)
+	ops := make([]entity.PostBatchOperation, 11)
+	for i := range ops {
+		ops[i] = createOp("created")
+	}
+	_, err := posts.Batch(member, entity.PostBatch{Operations: ops})
+
+	assert.True(t, errors.Is(err, constant.ErrBatchTooLarge), "got %v", err)
+	assert.Empty(t, repo.posts)
+
+	result, err := posts.Batch(member, entity.PostBatch{Operations: ops[:10]})
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Succeeded)
+}
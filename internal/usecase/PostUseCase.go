@@ -2,126 +2,352 @@ package usecase
 
 import (
 	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
 	"User-Post-Backend/internal/repository"
-	"encoding/json"
 	"errors"
 	"strconv"
 )
 
 type PostUsecase interface {
-	Create(post entity.CreatePost) error
-	CreateMultiplePosts(multiCreatePost entity.MultiCreatePost) error
-	GetAll() ([]entity.Post, error)
-	GetByID(id uint64) (entity.Post, error)
-	Update(post entity.UpdatePost) error
-	Delete(id uint64) error
+	Create(actor entity.Actor, post entity.CreatePost) (entity.Post, error)
+	CreateMultiplePosts(actor entity.Actor, multiCreatePost entity.MultiCreatePost) ([]entity.Post, error)
+	Batch(actor entity.Actor, batch entity.PostBatch) (entity.PostBatchResult, error)
+	GetAll(page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	GetByUser(userID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	GetByID(id uint64, include entity.PostInclude) (entity.Post, error)
+	Search(search entity.PostSearch, page entity.PageRequest) ([]entity.PostSearchResult, entity.PageMeta, error)
+	GetTags(page entity.PageRequest) ([]entity.TagCount, entity.PageMeta, error)
+	GetByTag(name string, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error)
+	Update(actor entity.Actor, post entity.UpdatePost, version uint64) (entity.Post, error)
+	Patch(actor entity.Actor, id uint64, patch entity.Patch, version uint64) (entity.Post, error)
+	Transfer(actor entity.Actor, id uint64, req entity.TransferPost, version uint64) (entity.Post, error)
+	Delete(actor entity.Actor, id uint64, version uint64) error
+	Restore(actor entity.Actor, id uint64) error
+	GetRevisions(id uint64, page entity.PageRequest) ([]entity.PostRevision, entity.PageMeta, error)
+	GetRevision(id uint64, revision int) (entity.RevisionDiff, error)
+	Rollback(actor entity.Actor, id uint64, revision int) (entity.Post, error)
 }
 
 type postUsecase struct {
-	repo  repository.PostRepository
-	cache *infra.RedisClient
+	repo         repository.PostRepository
+	revisionRepo repository.PostRevisionRepository
+	tagRepo      repository.TagRepository
+	userRepo     repository.UserRepository
+	cache        *infra.RedisClient
+	maxBatchSize int
 }
 
-func NewPostUsecase(repo repository.PostRepository, cache *infra.RedisClient) PostUsecase {
-	return &postUsecase{repo: repo, cache: cache}
+func NewPostUsecase(repo repository.PostRepository, revisionRepo repository.PostRevisionRepository, tagRepo repository.TagRepository, userRepo repository.UserRepository, cache *infra.RedisClient, maxBatchSize int) PostUsecase {
+	return &postUsecase{repo: repo, revisionRepo: revisionRepo, tagRepo: tagRepo, userRepo: userRepo, cache: cache, maxBatchSize: maxBatchSize}
 }
 
-func (p *postUsecase) Create(post entity.CreatePost) error {
-	err := p.repo.Create(post)
+func (p *postUsecase) Create(actor entity.Actor, post entity.CreatePost) (entity.Post, error) {
+	owner, err := postOwner(actor, post.UserID)
 	if err != nil {
-		return err
+		return entity.Post{}, err
 	}
+	post.UserID = owner
 
-	p.cache.Delete("posts")
-	return nil
+	created, err := p.repo.Create(post)
+	if err != nil {
+		return created, err
+	}
+
+	p.cache.Invalidate("posts")
+	return created, nil
+}
+
+// postOwner returns who a new post belongs to: the caller, unless another
+// user is named, which is reserved for those allowed to move posts between
+// users.
+func postOwner(actor entity.Actor, userID uint64) (uint64, error) {
+	if userID == 0 || userID == actor.UserID {
+		return actor.UserID, nil
+	}
+	if !actor.Can(constant.PermTransferPost) {
+		return 0, constant.ErrForbidden
+	}
+	return userID, nil
+}
+
+// Reads that embed the author skip the cache: the author belongs to the users
+// cache and would go stale here when the user changes.
+func (p *postUsecase) GetAll(page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	if include.Author {
+		return p.repo.GetAll(page, query, include)
+	}
+
+	key := pageCacheKey(p.cache, "posts", "all", page, query)
+	return cachedList(p.cache, key, func() ([]entity.Post, entity.PageMeta, error) {
+		return p.repo.GetAll(page, query, include)
+	})
 }
 
-func (p *postUsecase) GetAll() ([]entity.Post, error) {
-	cachedPosts, err := p.cache.Get("posts")
-	if err == nil && cachedPosts != "" {
-		var posts []entity.Post
-		json.Unmarshal([]byte(cachedPosts), &posts)
-		return posts, nil
+func (p *postUsecase) GetByUser(userID uint64, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	if include.Author {
+		return p.repo.GetByUserID(userID, page, query, include)
 	}
 
-	posts, err := p.repo.GetAll()
+	key := pageCacheKey(p.cache, "posts", "user="+strconv.Itoa(int(userID)), page, query)
+	return cachedList(p.cache, key, func() ([]entity.Post, entity.PageMeta, error) {
+		return p.repo.GetByUserID(userID, page, query, include)
+	})
+}
+
+func (p *postUsecase) GetByID(id uint64, include entity.PostInclude) (entity.Post, error) {
+	if include.Author {
+		return p.repo.GetByID(id, include)
+	}
+
+	return cached(p.cache, "post:"+strconv.Itoa(int(id)), func() (entity.Post, error) {
+		return p.repo.GetByID(id, include)
+	})
+}
+
+// Search is not cached: queries rarely repeat, and every write would have to
+// invalidate all of them.
+func (p *postUsecase) Search(search entity.PostSearch, page entity.PageRequest) ([]entity.PostSearchResult, entity.PageMeta, error) {
+	return p.repo.Search(search, page)
+}
+
+// Tags only change when posts are written, so the tag reads are cached in
+// the "posts" group: the invalidation every post write already does covers
+// them too.
+func (p *postUsecase) GetTags(page entity.PageRequest) ([]entity.TagCount, entity.PageMeta, error) {
+	key := pageCacheKey(p.cache, "posts", "tags", page, entity.ListQuery{})
+	return cachedList(p.cache, key, func() ([]entity.TagCount, entity.PageMeta, error) {
+		return p.tagRepo.GetAll(page)
+	})
+}
+
+func (p *postUsecase) GetByTag(name string, page entity.PageRequest, query entity.ListQuery, include entity.PostInclude) ([]entity.Post, entity.PageMeta, error) {
+	name = helpers.NormalizeTag(name)
+	if !helpers.IsTag(name) {
+		return nil, entity.PageMeta{}, constant.ErrRecordNotFound
+	}
+	tag, err := p.tagRepo.GetByName(name)
 	if err != nil {
-		return nil, err
+		return nil, entity.PageMeta{}, err
+	}
+	if include.Author {
+		return p.repo.GetByTag(tag.ID, page, query, include)
 	}
 
-	cachedData, _ := json.Marshal(posts)
-	p.cache.Set("posts", string(cachedData))
-	return posts, nil
+	key := pageCacheKey(p.cache, "posts", "tag="+name, page, query)
+	return cachedList(p.cache, key, func() ([]entity.Post, entity.PageMeta, error) {
+		return p.repo.GetByTag(tag.ID, page, query, include)
+	})
 }
 
-func (p *postUsecase) GetByID(id uint64) (entity.Post, error) {
-	cachedPost, err := p.cache.Get("post:" + strconv.Itoa(int(id)))
-	if err == nil && cachedPost != "" {
-		var post entity.Post
-		json.Unmarshal([]byte(cachedPost), &post)
-		return post, nil
+func (p *postUsecase) Update(actor entity.Actor, post entity.UpdatePost, version uint64) (entity.Post, error) {
+	existingPost, err := p.repo.GetByID(post.ID, entity.PostInclude{})
+	if err != nil {
+		return existingPost, err
+	}
+
+	if existingPost.UserID != actor.UserID && !actor.Can(constant.PermUpdateAnyPost) {
+		return existingPost, constant.ErrForbidden
 	}
 
-	post, err := p.repo.GetByID(id)
+	updated, err := p.repo.Update(post, actor.UserID, version)
 	if err != nil {
-		return post, err
+		return updated, err
 	}
 
-	cachedData, _ := json.Marshal(post)
-	p.cache.Set("post:"+strconv.Itoa(int(id)), string(cachedData))
-	return post, nil
+	p.cache.Delete("post:" + strconv.Itoa(int(post.ID)))
+	p.cache.Invalidate("posts")
+
+	return updated, nil
 }
 
-func (p *postUsecase) Update(post entity.UpdatePost) error {
-	existingPost, err := p.repo.GetByID(post.ID)
+// Patch applies a merge or JSON patch to the editable fields of the post and
+// writes only the columns it changed; a patch that changes nothing records no
+// revision.
+func (p *postUsecase) Patch(actor entity.Actor, id uint64, patch entity.Patch, version uint64) (entity.Post, error) {
+	existingPost, err := p.repo.GetByID(id, entity.PostInclude{})
 	if err != nil {
-		return err
+		return existingPost, err
+	}
+	if existingPost.UserID != actor.UserID && !actor.Can(constant.PermUpdateAnyPost) {
+		return existingPost, constant.ErrForbidden
+	}
+	if err := checkVersion(existingPost.Version, version); err != nil {
+		return existingPost, err
+	}
+
+	current := entity.PostPatch{Title: existingPost.Title, Content: existingPost.Content}
+	var patched entity.PostPatch
+	if err := helpers.ApplyPatch(patch, current, &patched); err != nil {
+		return existingPost, err
+	}
+	if err := helpers.Validate(patched); err != nil {
+		return existingPost, err
+	}
+
+	changes := entity.UpdatePost{ID: id}
+	if patched.Title != current.Title {
+		changes.Title = &patched.Title
+	}
+	if patched.Content != current.Content {
+		changes.Content = &patched.Content
+	}
+	if changes.Title == nil && changes.Content == nil {
+		return existingPost, nil
 	}
 
-	if existingPost.ID == 0 {
-		return errors.New("not found")
+	updated, err := p.repo.Update(changes, actor.UserID, version)
+	if err != nil {
+		return updated, err
 	}
 
-	err = p.repo.Update(post)
+	p.cache.Delete("post:" + strconv.Itoa(int(id)))
+	p.cache.Invalidate("posts")
+	return updated, nil
+}
+
+// Transfer moves a post to another user and is reserved for admins.
+func (p *postUsecase) Transfer(actor entity.Actor, id uint64, req entity.TransferPost, version uint64) (entity.Post, error) {
+	if !actor.Can(constant.PermTransferPost) {
+		return entity.Post{}, constant.ErrForbidden
+	}
+
+	if _, err := p.repo.GetByID(id, entity.PostInclude{}); err != nil {
+		return entity.Post{}, err
+	}
+	// Soft-deleted users are not found either, so no post moves to them.
+	if _, err := p.userRepo.GetByID(req.UserID); err != nil {
+		if errors.Is(err, constant.ErrNotFound) {
+			return entity.Post{}, constant.ErrUserNotFound
+		}
+		return entity.Post{}, err
+	}
+
+	err := p.repo.UpdateOwner(id, req.UserID, version)
+	if err != nil {
+		return entity.Post{}, err
+	}
+
+	p.cache.Delete("post:" + strconv.Itoa(int(id)))
+	p.cache.Invalidate("posts")
+	return p.repo.GetByID(id, entity.PostInclude{})
+}
+
+func (p *postUsecase) Delete(actor entity.Actor, id uint64, version uint64) error {
+	existingPost, err := p.repo.GetByID(id, entity.PostInclude{})
 	if err != nil {
 		return err
 	}
 
-	p.cache.Delete("post:" + strconv.Itoa(int(post.ID)))
-	p.cache.Delete("posts")
+	if existingPost.UserID != actor.UserID && !actor.Can(constant.PermDeleteAnyPost) {
+		return constant.ErrForbidden
+	}
 
+	err = p.repo.Delete(id, version)
+	if err != nil {
+		return err
+	}
+
+	p.cache.Delete("post:" + strconv.Itoa(int(id)))
+	p.cache.Invalidate("posts")
 	return nil
 }
 
-func (p *postUsecase) Delete(id uint64) error {
-	err := p.repo.Delete(id)
+func (p *postUsecase) Restore(actor entity.Actor, id uint64) error {
+	deletedPost, err := p.repo.GetDeletedByID(id)
+	if err != nil {
+		return err
+	}
+
+	if deletedPost.UserID != actor.UserID && !actor.Can(constant.PermDeleteAnyPost) {
+		return constant.ErrForbidden
+	}
+
+	err = p.repo.Restore(id)
 	if err != nil {
 		return err
 	}
 
 	p.cache.Delete("post:" + strconv.Itoa(int(id)))
-	p.cache.Delete("posts")
+	p.cache.Invalidate("posts")
 	return nil
 }
 
-func (p *postUsecase) CreateMultiplePosts(multiCreatePost entity.MultiCreatePost) error {
-	var posts []entity.Post
+func (p *postUsecase) GetRevisions(id uint64, page entity.PageRequest) ([]entity.PostRevision, entity.PageMeta, error) {
+	if _, err := p.repo.GetByID(id, entity.PostInclude{}); err != nil {
+		return nil, entity.PageMeta{}, err
+	}
+	return p.revisionRepo.GetAll(id, page)
+}
+
+func (p *postUsecase) GetRevision(id uint64, revision int) (entity.RevisionDiff, error) {
+	post, err := p.repo.GetByID(id, entity.PostInclude{})
+	if err != nil {
+		return entity.RevisionDiff{}, err
+	}
+
+	postRevision, err := p.revisionRepo.GetByRevision(id, revision)
+	if err != nil {
+		return entity.RevisionDiff{}, err
+	}
 
+	return entity.RevisionDiff{
+		PostRevision: postRevision,
+		TitleDiff:    helpers.LineDiff(postRevision.Title, post.Title),
+		ContentDiff:  helpers.LineDiff(postRevision.Content, post.Content),
+	}, nil
+}
+
+func (p *postUsecase) Rollback(actor entity.Actor, id uint64, revision int) (entity.Post, error) {
+	existingPost, err := p.repo.GetByID(id, entity.PostInclude{})
+	if err != nil {
+		return existingPost, err
+	}
+
+	if existingPost.UserID != actor.UserID && !actor.Can(constant.PermUpdateAnyPost) {
+		return existingPost, constant.ErrForbidden
+	}
+
+	postRevision, err := p.revisionRepo.GetByRevision(id, revision)
+	if err != nil {
+		return existingPost, err
+	}
+
+	updated, err := p.repo.Rollback(id, postRevision, actor.UserID)
+	if err != nil {
+		return updated, err
+	}
+
+	p.cache.Delete("post:" + strconv.Itoa(int(id)))
+	p.cache.Invalidate("posts")
+	return updated, nil
+}
+
+func (p *postUsecase) CreateMultiplePosts(actor entity.Actor, multiCreatePost entity.MultiCreatePost) ([]entity.Post, error) {
+	if len(multiCreatePost.Posts) > p.maxBatchSize {
+		return nil, constant.ErrBatchTooLarge.WithDetail("at most %d posts are allowed", p.maxBatchSize)
+	}
+	owner, err := postOwner(actor, multiCreatePost.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []entity.Post
 	for _, p := range multiCreatePost.Posts {
 		posts = append(posts, entity.Post{
 			Title:   p.Title,
 			Content: p.Content,
-			UserID:  multiCreatePost.UserID,
+			UserID:  owner,
 		})
 	}
 
-	err := p.repo.CreatePosts(posts)
+	created, err := p.repo.CreatePosts(posts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	p.cache.Delete("posts")
+	p.cache.Invalidate("posts")
 
-	return nil
+	return created, nil
 }
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	admin  = entity.Actor{UserID: 1, Role: constant.RoleAdmin}
+	member = entity.Actor{UserID: 2, Role: constant.RoleMember}
+)
+
+func newTestCache(t *testing.T) *infra.RedisClient {
+	return infra.NewRedisClientAt(miniredis.RunT(t).Addr())
+}
+
+func newTestPostUsecase(t *testing.T, posts ...entity.Post) (PostUsecase, *fakePostRepository) {
+	repo := newFakePostRepository(posts...)
+	users := newFakeUserRepository(entity.User{ID: 1}, entity.User{ID: 2}, entity.User{ID: 3})
+	return NewPostUsecase(repo, nil, nil, users, newTestCache(t), 10), repo
+}
+
+func TestCreatePostOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		actor  entity.Actor
+		userID uint64
+		owner  uint64
+		err    error
+	}{
+		{"defaults to the caller", member, 0, member.UserID, nil},
+		{"caller named", member, member.UserID, member.UserID, nil},
+		{"member names another user", member, 3, 0, constant.ErrForbidden},
+		{"admin names another user", admin, 3, 3, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			posts, _ := newTestPostUsecase(t)
+			created, err := posts.Create(test.actor, entity.CreatePost{Title: "t", Content: "c", UserID: test.userID})
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.owner, created.UserID)
+		})
+	}
+}
+
+func TestUpdatePost(t *testing.T) {
+	title := "new"
+	tests := []struct {
+		name  string
+		actor entity.Actor
+		id    uint64
+		err   error
+	}{
+		{"owner", member, 1, nil},
+		{"admin", admin, 1, nil},
+		{"another member", entity.Actor{UserID: 3, Role: constant.RoleMember}, 1, constant.ErrForbidden},
+		{"missing post", member, 9, constant.ErrRecordNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			posts, _ := newTestPostUsecase(t, entity.Post{ID: 1, Title: "old", UserID: member.UserID})
+			updated, err := posts.Update(test.actor, entity.UpdatePost{ID: test.id, Title: &title}, 0)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "new", updated.Title)
+		})
+	}
+}
+
+func TestTransferPost(t *testing.T) {
+	tests := []struct {
+		name   string
+		actor  entity.Actor
+		id     uint64
+		userID uint64
+		err    error
+	}{
+		{"admin", admin, 1, 3, nil},
+		{"member", member, 1, 3, constant.ErrForbidden},
+		{"missing post", admin, 9, 3, constant.ErrRecordNotFound},
+		{"missing user", admin, 1, 9, constant.ErrUserNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			posts, repo := newTestPostUsecase(t, entity.Post{ID: 1, UserID: member.UserID})
+			moved, err := posts.Transfer(test.actor, test.id, entity.TransferPost{UserID: test.userID}, 0)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "got %v", err)
+				assert.Equal(t, member.UserID, repo.posts[1].UserID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.userID, moved.UserID)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"User-Post-Backend/infra"
+	"User-Post-Backend/infra/logger"
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ReactionUsecase interface {
+	React(actor entity.Actor, postID uint64, reactionType string) (entity.ReactionCounts, error)
+	Unreact(actor entity.Actor, postID uint64, reactionType string) (entity.ReactionCounts, error)
+	Counts(postID uint64) (entity.ReactionCounts, error)
+	Reconcile() (entity.ReconcileResult, error)
+}
+
+// Reaction counts live in one Redis hash per post, adjusted on every
+// reaction and rebuilt from Postgres when missing. Postgres stays the source
+// of truth: Reconcile overwrites counters that drifted from it.
+const (
+	reactionCountersPrefix = "reactions:post"
+	reactionRepairedKey    = "reactions:repaired"
+	reconcileLockKey       = "reactions:reconcile:lock"
+)
+
+type reactionUsecase struct {
+	repo  repository.ReactionRepository
+	cache *infra.RedisClient
+}
+
+func NewReactionUsecase(repo repository.ReactionRepository, cache *infra.RedisClient) ReactionUsecase {
+	return &reactionUsecase{repo: repo, cache: cache}
+}
+
+// React is idempotent: reacting twice with the same type counts once.
+func (u *reactionUsecase) React(actor entity.Actor, postID uint64, reactionType string) (entity.ReactionCounts, error) {
+	reaction := entity.PostReaction{PostID: postID, UserID: actor.UserID, Type: reactionType}
+	return u.change(reaction, 1, u.repo.Add)
+}
+
+func (u *reactionUsecase) Unreact(actor entity.Actor, postID uint64, reactionType string) (entity.ReactionCounts, error) {
+	reaction := entity.PostReaction{PostID: postID, UserID: actor.UserID, Type: reactionType}
+	return u.change(reaction, -1, u.repo.Remove)
+}
+
+// change stores or removes reaction with write and, when write changed
+// anything, moves the counter of its type by delta.
+func (u *reactionUsecase) change(reaction entity.PostReaction, delta int64, write func(entity.PostReaction) (bool, error)) (entity.ReactionCounts, error) {
+	if !constant.IsValidReaction(reaction.Type) {
+		return nil, constant.ErrInvalidReaction
+	}
+	if err := u.repo.PostExists(reaction.PostID); err != nil {
+		return nil, err
+	}
+	// Load the counters first: incrementing a missing hash would leave only
+	// this type in it.
+	if _, err := u.Counts(reaction.PostID); err != nil {
+		return nil, err
+	}
+	changed, err := write(reaction)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		u.cache.HIncr(reactionCountersKey(reaction.PostID), reaction.Type, delta)
+	}
+	return u.Counts(reaction.PostID)
+}
+
+// Counts reads the counters of the post, rebuilding them from Postgres when
+// Redis has none. Types nobody used are left out. The caller checks that the
+// post exists, since a rebuild stores counters for any ID.
+func (u *reactionUsecase) Counts(postID uint64) (entity.ReactionCounts, error) {
+	key := reactionCountersKey(postID)
+	fields, err := u.cache.HGetAll(key)
+	if err == nil && len(fields) > 0 {
+		counts := entity.ReactionCounts{}
+		for reactionType, value := range fields {
+			count, _ := strconv.ParseInt(value, 10, 64)
+			if count > 0 {
+				counts[reactionType] = count
+			}
+		}
+		return counts, nil
+	}
+
+	counts, err := u.repo.CountByPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	u.cache.HSet(key, counterFields(counts))
+	return counts, nil
+}
+
+// Reconcile recounts every post from Postgres and overwrites the counters
+// that differ. Counters of posts without reactions are dropped, so the
+// all-zero ones Counts leaves for such posts do not pile up. Only one
+// instance runs it at a time.
+func (u *reactionUsecase) Reconcile() (entity.ReconcileResult, error) {
+	var result entity.ReconcileResult
+	locked, err := u.cache.SetNX(reconcileLockKey, "1", time.Minute)
+	if err != nil || !locked {
+		return result, err
+	}
+	defer u.cache.Delete(reconcileLockKey)
+
+	stored, err := u.repo.CountAll()
+	if err != nil {
+		return result, err
+	}
+	for postID, counts := range stored {
+		result.Posts++
+		key := reactionCountersKey(postID)
+		fields, err := u.cache.HGetAll(key)
+		if err != nil {
+			return result, err
+		}
+		if countersMatch(fields, counts) {
+			continue
+		}
+		if err := u.cache.HSet(key, counterFields(counts)); err != nil {
+			return result, err
+		}
+		result.Repaired++
+	}
+
+	keys, err := u.cache.ScanPrefix(reactionCountersPrefix)
+	if err != nil {
+		return result, err
+	}
+	for _, key := range keys {
+		postID, err := strconv.ParseUint(strings.TrimPrefix(key, reactionCountersPrefix+":"), 10, 64)
+		if err != nil || stored[postID] != nil {
+			continue
+		}
+		fields, err := u.cache.HGetAll(key)
+		if err != nil {
+			return result, err
+		}
+		if err := u.cache.Delete(key); err != nil {
+			return result, err
+		}
+		if !countersMatch(fields, nil) {
+			result.Repaired++
+		}
+	}
+
+	if result.Repaired > 0 {
+		logger.Infof("reconciled reaction counters of %d posts", result.Repaired)
+		u.cache.Incr(reactionRepairedKey, int64(result.Repaired))
+	}
+	return result, nil
+}
+
+func reactionCountersKey(postID uint64) string {
+	return reactionCountersPrefix + ":" + strconv.FormatUint(postID, 10)
+}
+
+// counterFields sets every known type, zero included, so a rebuilt hash
+// overwrites whatever drifted.
+func counterFields(counts entity.ReactionCounts) map[string]interface{} {
+	fields := make(map[string]interface{}, len(constant.ReactionTypes))
+	for _, reactionType := range constant.ReactionTypes {
+		fields[reactionType] = counts[reactionType]
+	}
+	return fields
+}
+
+func countersMatch(fields map[string]string, counts entity.ReactionCounts) bool {
+	for _, reactionType := range constant.ReactionTypes {
+		count, _ := strconv.ParseInt(fields[reactionType], 10, 64)
+		if count != counts[reactionType] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"User-Post-Backend/internal/constant"
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReactAndUnreact(t *testing.T) {
+	repo := newFakeReactionRepository(1)
+	repo.reactions[entity.PostReaction{PostID: 1, UserID: 3, Type: constant.ReactionLove}] = true
+	reactions := NewReactionUsecase(repo, newTestCache(t))
+
+	counts, err := reactions.React(member, 1, constant.ReactionLike)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReactionCounts{constant.ReactionLike: 1, constant.ReactionLove: 1}, counts)
+
+	counts, err = reactions.React(member, 1, constant.ReactionLike)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReactionCounts{constant.ReactionLike: 1, constant.ReactionLove: 1}, counts)
+
+	counts, err = reactions.Unreact(member, 1, constant.ReactionLike)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReactionCounts{constant.ReactionLove: 1}, counts)
+
+	counts, err = reactions.Unreact(member, 1, constant.ReactionLike)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReactionCounts{constant.ReactionLove: 1}, counts)
+}
+
+func TestReactRejects(t *testing.T) {
+	tests := []struct {
+		name         string
+		postID       uint64
+		reactionType string
+		err          error
+	}{
+		{"unknown type", 1, "meh", constant.ErrInvalidReaction},
+		{"missing post", 9, constant.ReactionLike, constant.ErrRecordNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reactions := NewReactionUsecase(newFakeReactionRepository(1), newTestCache(t))
+			_, err := reactions.React(member, test.postID, test.reactionType)
+			assert.True(t, errors.Is(err, test.err), "React: got %v", err)
+			_, err = reactions.Unreact(member, test.postID, test.reactionType)
+			assert.True(t, errors.Is(err, test.err), "Unreact: got %v", err)
+		})
+	}
+}
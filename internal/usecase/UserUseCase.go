@@ -2,19 +2,30 @@ package usecase
 
 import (
 	"User-Post-Backend/infra"
+	"User-Post-Backend/internal/constant"
 	"User-Post-Backend/internal/entity"
+	"User-Post-Backend/internal/helpers"
 	"User-Post-Backend/internal/repository"
 	"encoding/json"
-	"errors"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type UserUsecase interface {
-	Create(user entity.CreateUser) error
-	GetAll() ([]entity.User, error)
+	Create(user entity.CreateUser) (entity.User, error)
+	Register(user entity.RegisterUser) (entity.User, error)
+	ChangePassword(id uint64, req entity.ChangePassword) error
+	GetAll(page entity.PageRequest, query entity.ListQuery) ([]entity.User, entity.PageMeta, error)
 	GetByID(id uint64) (entity.User, error)
-	Update(user entity.User) error
-	Delete(id uint64) error
+	Autocomplete(text string, limit int) ([]entity.UserSuggestion, error)
+	Update(actor entity.Actor, user entity.User, version uint64) (entity.User, error)
+	Patch(actor entity.Actor, id uint64, patch entity.Patch, version uint64) (entity.User, error)
+	ChangeRole(id uint64, req entity.ChangeRole) (entity.User, error)
+	ChangeStatus(id uint64, req entity.ChangeStatus) (entity.User, error)
+	Delete(id uint64, version uint64) error
+	Restore(id uint64) error
 }
 
 type userUsecase struct {
@@ -26,70 +37,253 @@ func NewUserUsecase(repo repository.UserRepository, cache *infra.RedisClient) Us
 	return &userUsecase{repo: repo, cache: cache}
 }
 
-func (u *userUsecase) Create(user entity.CreateUser) error {
+func (u *userUsecase) Create(user entity.CreateUser) (entity.User, error) {
+	newUser := entity.User{
+		Name:   user.Name,
+		Email:  user.Email,
+		Status: constant.UserStatusActive,
+		Role:   constant.RoleMember,
+	}
+	if user.Password != "" {
+		hash, err := helpers.HashPassword(user.Password)
+		if err != nil {
+			return newUser, err
+		}
+		newUser.PasswordHash = hash
+	}
+	return u.create(newUser)
+}
 
-	err := u.repo.Create(user)
+func (u *userUsecase) Register(user entity.RegisterUser) (entity.User, error) {
+	hash, err := helpers.HashPassword(user.Password)
 	if err != nil {
-		return err
+		return entity.User{}, err
 	}
-	return u.cache.Delete("users")
+	return u.create(entity.User{
+		Name:         user.Name,
+		Email:        user.Email,
+		Status:       constant.UserStatusActive,
+		Role:         constant.RoleMember,
+		PasswordHash: hash,
+	})
 }
 
-func (u *userUsecase) GetAll() ([]entity.User, error) {
-	cachedUsers, err := u.cache.Get("users")
-	if err == nil && cachedUsers != "" {
-		var users []entity.User
-		json.Unmarshal([]byte(cachedUsers), &users)
-		return users, nil
+func (u *userUsecase) create(user entity.User) (entity.User, error) {
+	if user.Email != "" {
+		if _, err := u.repo.GetByEmail(user.Email); err == nil {
+			return user, constant.ErrEmailTaken
+		}
 	}
-	users, err := u.repo.GetAll()
+
+	created, err := u.repo.Create(user)
 	if err != nil {
-		return nil, err
+		return created, err
 	}
-	cachedData, _ := json.Marshal(users)
-	u.cache.Set("users", string(cachedData))
-	return users, nil
+	return created, u.cache.Invalidate("users")
+}
+
+// ChangePassword reads the user from the repository, never the cache, since
+// cached entries do not carry the password hash.
+func (u *userUsecase) ChangePassword(id uint64, req entity.ChangePassword) error {
+	user, err := u.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash != "" && !helpers.CheckPassword(user.PasswordHash, req.CurrentPassword) {
+		return constant.ErrInvalidCredentials
+	}
+
+	hash, err := helpers.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	return u.repo.UpdatePassword(id, hash)
+}
+
+func (u *userUsecase) GetAll(page entity.PageRequest, query entity.ListQuery) ([]entity.User, entity.PageMeta, error) {
+	key := pageCacheKey(u.cache, "users", "all", page, query)
+	return cachedList(u.cache, key, func() ([]entity.User, entity.PageMeta, error) {
+		return u.repo.GetAll(page, query)
+	})
 }
 
 func (u *userUsecase) GetByID(id uint64) (entity.User, error) {
-	cachedUser, err := u.cache.Get("user:" + strconv.Itoa(int(id)))
-	if err == nil && cachedUser != "" {
-		var user entity.User
-		json.Unmarshal([]byte(cachedUser), &user)
-		return user, nil
+	return cached(u.cache, "user:"+strconv.Itoa(int(id)), func() (entity.User, error) {
+		return u.repo.GetByID(id)
+	})
+}
+
+// Short prefixes are what every typeahead starts with, so they repeat across
+// users and are cached briefly; longer text is rarely repeated and goes to the
+// database. User writes clear the cache with the rest of "users".
+const (
+	autocompleteCachedLength = 3
+	autocompleteCacheTTL     = 30 * time.Second
+)
+
+func (u *userUsecase) Autocomplete(text string, limit int) ([]entity.UserSuggestion, error) {
+	text = strings.ToLower(text)
+	if utf8.RuneCountInString(text) > autocompleteCachedLength {
+		return u.repo.Autocomplete(text, limit)
 	}
-	user, err := u.repo.GetByID(id)
+
+	key := cacheKey(u.cache, "users", "autocomplete:"+strconv.Itoa(limit)+":"+text)
+	cachedSuggestions, err := u.cache.Get(key)
+	if err == nil && cachedSuggestions != "" {
+		var suggestions []entity.UserSuggestion
+		json.Unmarshal([]byte(cachedSuggestions), &suggestions)
+		return suggestions, nil
+	}
+	suggestions, err := u.repo.Autocomplete(text, limit)
 	if err != nil {
-		return user, err
+		return nil, err
 	}
-	cachedData, _ := json.Marshal(user)
-	u.cache.Set("user:"+strconv.Itoa(int(id)), string(cachedData))
-	return user, nil
+	cachedData, _ := json.Marshal(suggestions)
+	u.cache.SetTTL(key, string(cachedData), autocompleteCacheTTL)
+	return suggestions, nil
 }
 
-func (u *userUsecase) Update(user entity.User) error {
+func (u *userUsecase) Update(actor entity.Actor, user entity.User, version uint64) (entity.User, error) {
 	existingUser, err := u.repo.GetByID(user.ID)
 	if err != nil {
-		return err
+		return existingUser, err
 	}
 	if existingUser.ID == 0 {
-		return errors.New("data not found")
+		return existingUser, constant.ErrRecordNotFound
 	}
-	err = u.repo.Update(user)
+	if existingUser.ID != actor.UserID && !actor.Can(constant.PermManageUsers) {
+		return existingUser, constant.ErrForbidden
+	}
+	if err := checkVersion(existingUser.Version, version); err != nil {
+		return existingUser, err
+	}
+
+	// PUT keeps fields that are left empty.
+	changes := map[string]interface{}{}
+	if user.Name != "" {
+		changes["name"] = user.Name
+	}
+	if user.Email != "" {
+		changes["email"] = user.Email
+	}
+	if len(changes) == 0 {
+		return existingUser, nil
+	}
+	return u.update(user.ID, changes, version)
+}
+
+// Patch applies a merge or JSON patch to the editable fields of the user and
+// writes only the columns it changed. A cleared email is stored as NULL so it
+// does not collide with other users in users_email_key.
+func (u *userUsecase) Patch(actor entity.Actor, id uint64, patch entity.Patch, version uint64) (entity.User, error) {
+	existingUser, err := u.repo.GetByID(id)
+	if err != nil {
+		return existingUser, err
+	}
+	if existingUser.ID != actor.UserID && !actor.Can(constant.PermManageUsers) {
+		return existingUser, constant.ErrForbidden
+	}
+	if err := checkVersion(existingUser.Version, version); err != nil {
+		return existingUser, err
+	}
+
+	current := entity.UserPatch{Name: existingUser.Name, Email: existingUser.Email}
+	var patched entity.UserPatch
+	if err := helpers.ApplyPatch(patch, current, &patched); err != nil {
+		return existingUser, err
+	}
+	if err := helpers.Validate(patched); err != nil {
+		return existingUser, err
+	}
+
+	changes := map[string]interface{}{}
+	if patched.Name != current.Name {
+		changes["name"] = patched.Name
+	}
+	if patched.Email != current.Email {
+		changes["email"] = patched.Email
+		if patched.Email == "" {
+			changes["email"] = nil
+		}
+	}
+	if len(changes) == 0 {
+		return existingUser, nil
+	}
+	return u.update(id, changes, version)
+}
+
+// update relies on users_email_key, translated to ErrEmailTaken, to reject
+// an email that belongs to another user.
+func (u *userUsecase) update(id uint64, changes map[string]interface{}, version uint64) (entity.User, error) {
+	updated, err := u.repo.Update(id, changes, version)
+	if err != nil {
+		return updated, err
+	}
+	u.cache.Delete("user:" + strconv.Itoa(int(id)))
+	u.cache.Invalidate("users")
+	return updated, nil
+}
+
+func (u *userUsecase) ChangeRole(id uint64, req entity.ChangeRole) (entity.User, error) {
+	if !constant.IsValidRole(req.Role) {
+		return entity.User{}, constant.ErrInvalidRole
+	}
+	if _, err := u.repo.GetByID(id); err != nil {
+		return entity.User{}, err
+	}
+	err := u.repo.UpdateRole(id, req.Role)
+	if err != nil {
+		return entity.User{}, err
+	}
+	u.cache.Delete("user:" + strconv.Itoa(int(id)))
+	u.cache.Invalidate("users")
+	return u.repo.GetByID(id)
+}
+
+func (u *userUsecase) ChangeStatus(id uint64, req entity.ChangeStatus) (entity.User, error) {
+	switch req.Status {
+	case constant.UserStatusActive, constant.UserStatusSuspended, constant.UserStatusDeleted:
+	default:
+		return entity.User{}, constant.ErrInvalidStatus
+	}
+	if _, err := u.repo.GetByID(id); err != nil {
+		return entity.User{}, err
+	}
+	err := u.repo.UpdateStatus(id, req.Status)
+	if err != nil {
+		return entity.User{}, err
+	}
+	u.cache.Delete("user:" + strconv.Itoa(int(id)))
+	u.cache.Invalidate("users")
+	return u.repo.GetByID(id)
+}
+
+// Delete also removes the user's posts, so their cached copies and the post
+// listings are invalidated too.
+func (u *userUsecase) Delete(id uint64, version uint64) error {
+	postIDs, err := u.repo.Delete(id, version)
 	if err != nil {
 		return err
 	}
-	u.cache.Delete("user:" + strconv.Itoa(int(user.ID)))
-	u.cache.Delete("users")
+	u.forget(id, postIDs)
 	return nil
 }
 
-func (u *userUsecase) Delete(id uint64) error {
-	err := u.repo.Delete(id)
+// Restore also brings back the posts removed together with the user.
+func (u *userUsecase) Restore(id uint64) error {
+	postIDs, err := u.repo.Restore(id)
 	if err != nil {
 		return err
 	}
-	u.cache.Delete("user:" + strconv.Itoa(int(id)))
-	u.cache.Delete("users")
+	u.forget(id, postIDs)
 	return nil
 }
+
+func (u *userUsecase) forget(id uint64, postIDs []uint64) {
+	u.cache.Delete("user:" + strconv.Itoa(int(id)))
+	for _, postID := range postIDs {
+		u.cache.Delete("post:" + strconv.Itoa(int(postID)))
+	}
+	u.cache.Invalidate("users")
+	u.cache.Invalidate("posts")
+}
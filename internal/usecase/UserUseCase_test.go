@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"testing"
+
+	"User-Post-Backend/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteAndRestoreUserForgetCascadedPosts(t *testing.T) {
+	cache := newTestCache(t)
+	repo := newFakeUserRepository(entity.User{ID: 1}, entity.User{ID: 2})
+	repo.posts[1] = []uint64{10, 11}
+	users := NewUserUsecase(repo, cache)
+
+	cachedKeys := []string{"user:1", "post:10", "post:11", "post:20"}
+	seed := func() {
+		for _, key := range cachedKeys {
+			require.NoError(t, cache.Set(key, "{}"))
+		}
+	}
+	assertCached := func(want map[string]bool) {
+		for _, key := range cachedKeys {
+			value, err := cache.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, want[key], value != "", key)
+		}
+	}
+
+	seed()
+	postsGeneration := cache.Generation("posts")
+	require.NoError(t, users.Delete(1, 0))
+	assertCached(map[string]bool{"post:20": true})
+	assert.Greater(t, cache.Generation("posts"), postsGeneration)
+
+	seed()
+	require.NoError(t, users.Restore(1))
+	assertCached(map[string]bool{"post:20": true})
+}
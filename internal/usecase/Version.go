@@ -0,0 +1,13 @@
+package usecase
+
+import "User-Post-Backend/internal/constant"
+
+// checkVersion fails early when the version the client expects is already
+// stale, before any work is done; the repository repeats the check on write.
+// An expected version of 0 accepts any.
+func checkVersion(current uint64, expected uint64) error {
+	if expected != 0 && current != expected {
+		return constant.ErrVersionMismatch
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"User-Post-Backend/infra/logger"
 	"User-Post-Backend/internal/handlers"
 	"User-Post-Backend/internal/middleware"
+	"User-Post-Backend/internal/repository"
 	"log"
 	"os"
 
@@ -21,19 +22,37 @@ import (
 // @description API documentation for UserPost backend.
 // @host localhost:3000
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	logger.InitializeLogger("app.log")
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.HandleError})
 	app.Get("/swagger/*", swagger.HandlerDefault)
 	app.Use(middleware.CORS())
 	app.Use(middleware.ErrorHandlerMiddleware)
 
 	db := infra.InitDB()
 	cache := infra.NewRedisClient()
+	tokens := infra.NewJWTManager()
 
+	// Posts are indexed and searched with the same configuration.
+	searchLanguage := infra.EnvString("SEARCH_LANGUAGE", "english")
+	if err := repository.ApplySearchLanguage(db, searchLanguage); err != nil {
+		log.Fatal("Invalid SEARCH_LANGUAGE: ", err)
+	}
+
+	users := repository.NewUserRepository(db)
+	app.Use("/api/v1", middleware.Authenticate(tokens, users.GetByID, middleware.PublicRoutes...))
+
+	handlers.NewAuthHandler(app, db, tokens)
 	handlers.NewUserHandler(app, db, cache)
-	handlers.NewPostHandler(app, db, cache)
+	handlers.NewPostHandler(app, db, cache, searchLanguage)
+	handlers.NewCommentHandler(app, db)
+	handlers.NewReactionHandler(app, db, cache)
+	handlers.NewFollowHandler(app, db)
+	handlers.NewAdminHandler(app, db, cache)
 
 	err := godotenv.Load()
 	if err != nil {